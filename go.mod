@@ -1,8 +1,10 @@
 module github.com/biogo/store
 
-go 1.14
+go 1.18
+
+require gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15
 
 require (
 	github.com/kr/pretty v0.2.0 // indirect
-	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15
+	github.com/kr/text v0.1.0 // indirect
 )
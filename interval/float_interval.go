@@ -0,0 +1,828 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interval
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/biogo/store/llrb"
+)
+
+// A FloatOverlapper can determine whether it overlaps a float range.
+type FloatOverlapper interface {
+	// Overlap returns a boolean indicating whether the receiver overlaps a range.
+	Overlap(FloatRange) bool
+}
+
+// A FloatRange is a type that describes the basic characteristics of an interval over the
+// real number line. Start and End may be infinite, but must not be NaN.
+type FloatRange struct {
+	Start, End float64
+}
+
+// A FloatInterface is a type that can be inserted into a FloatTree.
+type FloatInterface interface {
+	FloatOverlapper
+	Range() FloatRange
+	ID() uintptr // Returns a unique ID for the element.
+}
+
+// A FloatNode represents a node in a FloatTree.
+type FloatNode struct {
+	Elem        FloatInterface
+	Interval    FloatRange
+	Range       FloatRange
+	Left, Right *FloatNode
+	Color       llrb.Color
+}
+
+// A FloatTree manages the root node of a real line interval tree.
+// Public methods are exposed through this type.
+type FloatTree struct {
+	Root  *FloatNode // Root node of the tree.
+	Count int        // Number of elements stored.
+}
+
+// Helper methods
+
+// floatCompare returns the sort order relationship between a and b, which
+// must not be NaN. Subtraction is not used since it is not well defined for
+// infinite values.
+func floatCompare(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// checkFloatRange returns an error if r can not be stored in a FloatTree.
+func checkFloatRange(r FloatRange) error {
+	if math.IsNaN(r.Start) || math.IsNaN(r.End) {
+		return ErrNaNRange
+	}
+	if r.Start > r.End {
+		return ErrInvertedRange
+	}
+	return nil
+}
+
+// color returns the effect color of a FloatNode. A nil node returns black.
+func (n *FloatNode) color() llrb.Color {
+	if n == nil {
+		return llrb.Black
+	}
+	return n.Color
+}
+
+// floatMaxRange returns the furthest right position held by the subtree
+// rooted at root, assuming that the left and right nodes have correct
+// range extents.
+func floatMaxRange(root, left, right *FloatNode) float64 {
+	end := root.Interval.End
+	if left != nil && left.Range.End > end {
+		end = left.Range.End
+	}
+	if right != nil && right.Range.End > end {
+		end = right.Range.End
+	}
+	return end
+}
+
+// (a,c)b -rotL-> ((a,)b,)c
+func (n *FloatNode) rotateLeft() (root *FloatNode) {
+	// Assumes: n has a right child.
+	root = n.Right
+	n.Right = root.Left
+	root.Left = n
+	root.Color = n.Color
+	n.Color = llrb.Red
+
+	root.Left.Range.End = floatMaxRange(root.Left, root.Left.Left, root.Left.Right)
+	if root.Left == nil {
+		root.Range.Start = root.Interval.Start
+	} else {
+		root.Range.Start = root.Left.Range.Start
+	}
+	root.Range.End = floatMaxRange(root, root.Left, root.Right)
+
+	return
+}
+
+// (a,c)b -rotR-> (,(,c)b)a
+func (n *FloatNode) rotateRight() (root *FloatNode) {
+	// Assumes: n has a left child.
+	root = n.Left
+	n.Left = root.Right
+	root.Right = n
+	root.Color = n.Color
+	n.Color = llrb.Red
+
+	if root.Right.Left == nil {
+		root.Right.Range.Start = root.Right.Interval.Start
+	} else {
+		root.Right.Range.Start = root.Right.Left.Range.Start
+	}
+	root.Right.Range.End = floatMaxRange(root.Right, root.Right.Left, root.Right.Right)
+	root.Range.End = floatMaxRange(root, root.Left, root.Right)
+
+	return
+}
+
+// (aR,cR)bB -flipC-> (aB,cB)bR | (aB,cB)bR -flipC-> (aR,cR)bB
+func (n *FloatNode) flipColors() {
+	// Assumes: n has two children.
+	n.Color = !n.Color
+	n.Left.Color = !n.Left.Color
+	n.Right.Color = !n.Right.Color
+}
+
+// fixUp ensures that black link balance is correct, that red nodes lean left,
+// and that 4 nodes are split in the case of BU23 and properly balanced in TD234.
+func (n *FloatNode) fixUp(fast bool) *FloatNode {
+	if !fast {
+		n.adjustRange()
+	}
+	if n.Right.color() == llrb.Red {
+		if Mode == TD234 && n.Right.Left.color() == llrb.Red {
+			n.Right = n.Right.rotateRight()
+		}
+		n = n.rotateLeft()
+	}
+	if n.Left.color() == llrb.Red && n.Left.Left.color() == llrb.Red {
+		n = n.rotateRight()
+	}
+	if Mode == BU23 && n.Left.color() == llrb.Red && n.Right.color() == llrb.Red {
+		n.flipColors()
+	}
+
+	return n
+}
+
+// adjustRange sets the Range to the maximum extent of the childrens' Range
+// spans and the node's Elem span.
+func (n *FloatNode) adjustRange() {
+	if n.Left == nil {
+		n.Range.Start = n.Interval.Start
+	} else {
+		n.Range.Start = n.Left.Range.Start
+	}
+	n.Range.End = floatMaxRange(n, n.Left, n.Right)
+}
+
+func (n *FloatNode) moveRedLeft() *FloatNode {
+	n.flipColors()
+	if n.Right.Left.color() == llrb.Red {
+		n.Right = n.Right.rotateRight()
+		n = n.rotateLeft()
+		n.flipColors()
+		if Mode == TD234 && n.Right.Right.color() == llrb.Red {
+			n.Right = n.Right.rotateLeft()
+		}
+	}
+	return n
+}
+
+func (n *FloatNode) moveRedRight() *FloatNode {
+	n.flipColors()
+	if n.Left.Left.color() == llrb.Red {
+		n = n.rotateRight()
+		n.flipColors()
+	}
+	return n
+}
+
+// Len returns the number of intervals stored in the FloatTree.
+func (t *FloatTree) Len() int {
+	return t.Count
+}
+
+// Stats returns summary statistics for the FloatTree.
+func (t *FloatTree) Stats() llrb.Stats {
+	var s llrb.Stats
+	s.Height = t.Root.stats(&s, 0)
+	if s.Nodes != 0 {
+		s.MeanDepth /= float64(s.Nodes)
+	}
+	for n := t.Root; n != nil; n = n.Left {
+		if n.color() == llrb.Black {
+			s.BlackHeight++
+		}
+	}
+	s.Bytes = s.Nodes * int(unsafe.Sizeof(FloatNode{}))
+	return s
+}
+
+// stats accumulates node counts and the sum of node depths into s, returning
+// the height of the subtree rooted at n.
+func (n *FloatNode) stats(s *llrb.Stats, depth int) (height int) {
+	if n == nil {
+		return 0
+	}
+	s.Nodes++
+	s.MeanDepth += float64(depth)
+	if n.Left == nil && n.Right == nil {
+		s.Leaves++
+	}
+	height = n.Left.stats(s, depth+1)
+	if h := n.Right.stats(s, depth+1); h > height {
+		height = h
+	}
+	return height + 1
+}
+
+// Get returns a slice of FloatInterfaces that overlap q in the FloatTree according
+// to q.Overlap().
+func (t *FloatTree) Get(q FloatOverlapper) (o []FloatInterface) {
+	if t.Root != nil && q.Overlap(t.Root.Range) {
+		t.Root.doMatch(func(e FloatInterface) (done bool) { o = append(o, e); return }, q)
+	}
+	return
+}
+
+// AdjustRanges fixes range fields for all FloatNodes in the FloatTree. This must be called
+// before Get or DoMatching* is used if fast insertion or deletion has been performed.
+func (t *FloatTree) AdjustRanges() {
+	if t.Root == nil {
+		return
+	}
+	t.Root.adjustRanges()
+}
+
+func (n *FloatNode) adjustRanges() {
+	if n.Left != nil {
+		n.Left.adjustRanges()
+	}
+	if n.Right != nil {
+		n.Right.adjustRanges()
+	}
+	n.adjustRange()
+}
+
+// Insert inserts the FloatInterface e into the FloatTree. Insertions may replace
+// existing stored intervals. Intervals with infinite end points may be inserted,
+// but if either end point is NaN, ErrNaNRange is returned.
+func (t *FloatTree) Insert(e FloatInterface, fast bool) (err error) {
+	if err = checkFloatRange(e.Range()); err != nil {
+		return
+	}
+	var d int
+	t.Root, d = t.Root.insert(e, e.Range(), e.ID(), fast)
+	t.Count += d
+	t.Root.Color = llrb.Black
+	return
+}
+
+func (n *FloatNode) insert(e FloatInterface, r FloatRange, id uintptr, fast bool) (root *FloatNode, d int) {
+	if n == nil {
+		return &FloatNode{Elem: e, Interval: r, Range: r}, 1
+	} else if n.Elem == nil {
+		n.Elem = e
+		n.Interval = r
+		if !fast {
+			n.adjustRange()
+		}
+		return n, 1
+	}
+
+	if Mode == TD234 {
+		if n.Left.color() == llrb.Red && n.Right.color() == llrb.Red {
+			n.flipColors()
+		}
+	}
+
+	switch c := floatCompare(r.Start, n.Interval.Start); {
+	case c == 0:
+		switch {
+		case id == n.Elem.ID():
+			n.Elem = e
+			n.Interval = r
+			if !fast {
+				n.Range.End = r.End
+			}
+		case id < n.Elem.ID():
+			n.Left, d = n.Left.insert(e, r, id, fast)
+		default:
+			n.Right, d = n.Right.insert(e, r, id, fast)
+		}
+	case c < 0:
+		n.Left, d = n.Left.insert(e, r, id, fast)
+	default:
+		n.Right, d = n.Right.insert(e, r, id, fast)
+	}
+
+	if n.Right.color() == llrb.Red && n.Left.color() == llrb.Black {
+		n = n.rotateLeft()
+	}
+	if n.Left.color() == llrb.Red && n.Left.Left.color() == llrb.Red {
+		n = n.rotateRight()
+	}
+
+	if Mode == BU23 {
+		if n.Left.color() == llrb.Red && n.Right.color() == llrb.Red {
+			n.flipColors()
+		}
+	}
+
+	if !fast {
+		n.adjustRange()
+	}
+	root = n
+
+	return
+}
+
+// DeleteMin deletes the left-most interval.
+func (t *FloatTree) DeleteMin(fast bool) {
+	if t.Root == nil {
+		return
+	}
+	var d int
+	t.Root, d = t.Root.deleteMin(fast)
+	t.Count += d
+	if t.Root == nil {
+		return
+	}
+	t.Root.Color = llrb.Black
+}
+
+func (n *FloatNode) deleteMin(fast bool) (root *FloatNode, d int) {
+	if n.Left == nil {
+		return nil, -1
+	}
+	if n.Left.color() == llrb.Black && n.Left.Left.color() == llrb.Black {
+		n = n.moveRedLeft()
+	}
+	n.Left, d = n.Left.deleteMin(fast)
+	if n.Left == nil {
+		n.Range.Start = n.Elem.Range().Start
+	}
+
+	root = n.fixUp(fast)
+
+	return
+}
+
+// DeleteMax deletes the right-most interval.
+func (t *FloatTree) DeleteMax(fast bool) {
+	if t.Root == nil {
+		return
+	}
+	var d int
+	t.Root, d = t.Root.deleteMax(fast)
+	t.Count += d
+	if t.Root == nil {
+		return
+	}
+	t.Root.Color = llrb.Black
+}
+
+func (n *FloatNode) deleteMax(fast bool) (root *FloatNode, d int) {
+	if n.Left != nil && n.Left.color() == llrb.Red {
+		n = n.rotateRight()
+	}
+	if n.Right == nil {
+		return nil, -1
+	}
+	if n.Right.color() == llrb.Black && n.Right.Left.color() == llrb.Black {
+		n = n.moveRedRight()
+	}
+	n.Right, d = n.Right.deleteMax(fast)
+	if n.Right == nil {
+		n.Range.End = n.Elem.Range().End
+	}
+
+	root = n.fixUp(fast)
+
+	return
+}
+
+// Delete deletes the element e if it exists in the FloatTree.
+func (t *FloatTree) Delete(e FloatInterface, fast bool) (err error) {
+	if err = checkFloatRange(e.Range()); err != nil {
+		return
+	}
+	if t.Root == nil || !e.Overlap(t.Root.Range) {
+		return
+	}
+	var d int
+	t.Root, d = t.Root.delete(e.Range().Start, e.ID(), fast)
+	t.Count += d
+	if t.Root == nil {
+		return
+	}
+	t.Root.Color = llrb.Black
+	return
+}
+
+func (n *FloatNode) delete(m float64, id uintptr, fast bool) (root *FloatNode, d int) {
+	if p := floatCompare(m, n.Interval.Start); p < 0 || (p == 0 && id < n.Elem.ID()) {
+		if n.Left != nil {
+			if n.Left.color() == llrb.Black && n.Left.Left.color() == llrb.Black {
+				n = n.moveRedLeft()
+			}
+			n.Left, d = n.Left.delete(m, id, fast)
+			if n.Left == nil {
+				n.Range.Start = n.Interval.Start
+			}
+		}
+	} else {
+		if n.Left.color() == llrb.Red {
+			n = n.rotateRight()
+		}
+		if n.Right == nil && id == n.Elem.ID() {
+			return nil, -1
+		}
+		if n.Right != nil {
+			if n.Right.color() == llrb.Black && n.Right.Left.color() == llrb.Black {
+				n = n.moveRedRight()
+			}
+			if id == n.Elem.ID() {
+				m := n.Right.min()
+				n.Elem = m.Elem
+				n.Interval = m.Interval
+				n.Right, d = n.Right.deleteMin(fast)
+			} else {
+				n.Right, d = n.Right.delete(m, id, fast)
+			}
+			if n.Right == nil {
+				n.Range.End = n.Interval.End
+			}
+		}
+	}
+
+	root = n.fixUp(fast)
+
+	return
+}
+
+// Update relocates the element e in the FloatTree, where old is the range of e when it was
+// last inserted or updated. The range augmentation of the tree is corrected. If no element
+// with the ID of e is stored with the range old, ErrStaleRange is returned and the tree is
+// not altered.
+func (t *FloatTree) Update(e FloatInterface, old FloatRange) (err error) {
+	r := e.Range()
+	if err = checkFloatRange(r); err != nil {
+		return
+	}
+	id := e.ID()
+	n := t.Root.find(old.Start, id)
+	if n == nil || n.Interval != old {
+		return ErrStaleRange
+	}
+	if r.Start == old.Start {
+		t.Root.update(e, r, id)
+		return
+	}
+
+	var d int
+	t.Root, d = t.Root.delete(old.Start, id, false)
+	t.Count += d
+	if t.Root != nil {
+		t.Root.Color = llrb.Black
+	}
+	return t.Insert(e, false)
+}
+
+// find returns the node holding the element with the given ID and start m.
+func (n *FloatNode) find(m float64, id uintptr) *FloatNode {
+	for n != nil {
+		switch c := floatCompare(m, n.Interval.Start); {
+		case c == 0 && id == n.Elem.ID():
+			return n
+		case c < 0 || (c == 0 && id < n.Elem.ID()):
+			n = n.Left
+		default:
+			n = n.Right
+		}
+	}
+	return nil
+}
+
+// update replaces the element with the given ID and start r.Start with e, correcting
+// the range augmentation on the path to the element.
+func (n *FloatNode) update(e FloatInterface, r FloatRange, id uintptr) {
+	switch c := floatCompare(r.Start, n.Interval.Start); {
+	case c == 0 && id == n.Elem.ID():
+		n.Elem = e
+		n.Interval = r
+	case c < 0 || (c == 0 && id < n.Elem.ID()):
+		n.Left.update(e, r, id)
+	default:
+		n.Right.update(e, r, id)
+	}
+	n.adjustRange()
+}
+
+// Return the left-most interval stored in the tree.
+func (t *FloatTree) Min() FloatInterface {
+	if t.Root == nil {
+		return nil
+	}
+	return t.Root.min().Elem
+}
+
+func (n *FloatNode) min() *FloatNode {
+	for ; n.Left != nil; n = n.Left {
+	}
+	return n
+}
+
+// Return the right-most interval stored in the tree.
+func (t *FloatTree) Max() FloatInterface {
+	if t.Root == nil {
+		return nil
+	}
+	return t.Root.max().Elem
+}
+
+func (n *FloatNode) max() *FloatNode {
+	for ; n.Right != nil; n = n.Right {
+	}
+	return n
+}
+
+// Floor returns the largest value equal to or less than the query q according to
+// q.Range().Start, with ties broken by comparison of ID() values. If the start of q
+// is NaN, ErrNaNRange is returned.
+func (t *FloatTree) Floor(q FloatInterface) (o FloatInterface, err error) {
+	m := q.Range().Start
+	if math.IsNaN(m) {
+		return nil, ErrNaNRange
+	}
+	if t.Root == nil {
+		return
+	}
+	n := t.Root.floor(m, q.ID())
+	if n == nil {
+		return
+	}
+	return n.Elem, nil
+}
+
+func (n *FloatNode) floor(m float64, id uintptr) *FloatNode {
+	if n == nil {
+		return nil
+	}
+	switch c := floatCompare(m, n.Interval.Start); {
+	case c == 0:
+		switch {
+		case id == n.Elem.ID():
+			return n
+		case id < n.Elem.ID():
+			return n.Left.floor(m, id)
+		default:
+			if r := n.Right.floor(m, id); r != nil {
+				return r
+			}
+		}
+	case c < 0:
+		return n.Left.floor(m, id)
+	default:
+		if r := n.Right.floor(m, id); r != nil {
+			return r
+		}
+	}
+	return n
+}
+
+// Ceil returns the smallest value equal to or greater than the query q according to
+// q.Range().Start, with ties broken by comparison of ID() values. If the start of q
+// is NaN, ErrNaNRange is returned.
+func (t *FloatTree) Ceil(q FloatInterface) (o FloatInterface, err error) {
+	m := q.Range().Start
+	if math.IsNaN(m) {
+		return nil, ErrNaNRange
+	}
+	if t.Root == nil {
+		return
+	}
+	n := t.Root.ceil(m, q.ID())
+	if n == nil {
+		return
+	}
+	return n.Elem, nil
+}
+
+func (n *FloatNode) ceil(m float64, id uintptr) *FloatNode {
+	if n == nil {
+		return nil
+	}
+	switch c := floatCompare(m, n.Interval.Start); {
+	case c == 0:
+		switch {
+		case id == n.Elem.ID():
+			return n
+		case id > n.Elem.ID():
+			return n.Right.ceil(m, id)
+		default:
+			if l := n.Left.ceil(m, id); l != nil {
+				return l
+			}
+		}
+	case c > 0:
+		return n.Right.ceil(m, id)
+	default:
+		if l := n.Left.ceil(m, id); l != nil {
+			return l
+		}
+	}
+	return n
+}
+
+// An FloatOperation is a function that operates on an FloatInterface. If done is returned true, the
+// FloatOperation is indicating that no further work needs to be done and so the Do function should
+// traverse no further.
+type FloatOperation func(FloatInterface) (done bool)
+
+// Do performs fn on all intervals stored in the tree. A boolean is returned indicating whether the
+// Do traversal was interrupted by an FloatOperation returning true. If fn alters stored intervals'
+// end points, future tree operation behaviors are undefined.
+func (t *FloatTree) Do(fn FloatOperation) bool {
+	if t.Root == nil {
+		return false
+	}
+	return t.Root.do(fn)
+}
+
+func (n *FloatNode) do(fn FloatOperation) (done bool) {
+	if n.Left != nil {
+		done = n.Left.do(fn)
+		if done {
+			return
+		}
+	}
+	done = fn(n.Elem)
+	if done {
+		return
+	}
+	if n.Right != nil {
+		done = n.Right.do(fn)
+	}
+	return
+}
+
+// DoReverse performs fn on all intervals stored in the tree, but in reverse of sort order. A boolean
+// is returned indicating whether the Do traversal was interrupted by an FloatOperation returning true.
+// If fn alters stored intervals' end points, future tree operation behaviors are undefined.
+func (t *FloatTree) DoReverse(fn FloatOperation) bool {
+	if t.Root == nil {
+		return false
+	}
+	return t.Root.doReverse(fn)
+}
+
+func (n *FloatNode) doReverse(fn FloatOperation) (done bool) {
+	if n.Right != nil {
+		done = n.Right.doReverse(fn)
+		if done {
+			return
+		}
+	}
+	done = fn(n.Elem)
+	if done {
+		return
+	}
+	if n.Left != nil {
+		done = n.Left.doReverse(fn)
+	}
+	return
+}
+
+// DoMatch performs fn on all intervals stored in the tree that match q according to Overlap, with
+// q.Overlap() used to guide tree traversal, so DoMatching() will out perform Do() with a called
+// conditional function if the condition is based on sort order, but can not be reliably used if
+// the condition is independent of sort order. A boolean is returned indicating whether the Do
+// traversal was interrupted by an FloatOperation returning true. If fn alters stored intervals' end
+// points, future tree operation behaviors are undefined.
+func (t *FloatTree) DoMatching(fn FloatOperation, q FloatOverlapper) bool {
+	if t.Root != nil && q.Overlap(t.Root.Range) {
+		return t.Root.doMatch(fn, q)
+	}
+	return false
+}
+
+func (n *FloatNode) doMatch(fn FloatOperation, q FloatOverlapper) (done bool) {
+	if n.Left != nil && q.Overlap(n.Left.Range) {
+		done = n.Left.doMatch(fn, q)
+		if done {
+			return
+		}
+	}
+	if q.Overlap(n.Interval) {
+		done = fn(n.Elem)
+		if done {
+			return
+		}
+	}
+	if n.Right != nil && q.Overlap(n.Right.Range) {
+		done = n.Right.doMatch(fn, q)
+	}
+	return
+}
+
+// DoMatchReverse performs fn on all intervals stored in the tree that match q according to Overlap,
+// with q.Overlap() used to guide tree traversal, so DoMatching() will out perform Do() with a called
+// conditional function if the condition is based on sort order, but can not be reliably used if
+// the condition is independent of sort order. A boolean is returned indicating whether the Do
+// traversal was interrupted by an FloatOperation returning true. If fn alters stored intervals' end
+// points, future tree operation behaviors are undefined.
+func (t *FloatTree) DoMatchingReverse(fn FloatOperation, q FloatOverlapper) bool {
+	if t.Root != nil && q.Overlap(t.Root.Range) {
+		return t.Root.doMatchReverse(fn, q)
+	}
+	return false
+}
+
+func (n *FloatNode) doMatchReverse(fn FloatOperation, q FloatOverlapper) (done bool) {
+	if n.Right != nil && q.Overlap(n.Right.Range) {
+		done = n.Right.doMatchReverse(fn, q)
+		if done {
+			return
+		}
+	}
+	if q.Overlap(n.Interval) {
+		done = fn(n.Elem)
+		if done {
+			return
+		}
+	}
+	if n.Left != nil && q.Overlap(n.Left.Range) {
+		done = n.Left.doMatchReverse(fn, q)
+	}
+	return
+}
+
+// DoContaining performs fn on all intervals stored in the tree that contain q, that is intervals
+// with a start no greater than q.Start and an end no less than q.End. Tree traversal is pruned
+// using the sort order of interval starts and the range of each subtree. A boolean is returned
+// indicating whether the Do traversal was interrupted by an FloatOperation returning true. If fn
+// alters stored intervals' end points, future tree operation behaviors are undefined.
+func (t *FloatTree) DoContaining(fn FloatOperation, q FloatRange) bool {
+	if t.Root != nil && t.Root.Range.End >= q.End {
+		return t.Root.doContaining(fn, q)
+	}
+	return false
+}
+
+func (n *FloatNode) doContaining(fn FloatOperation, q FloatRange) (done bool) {
+	if n.Left != nil && n.Left.Range.End >= q.End {
+		done = n.Left.doContaining(fn, q)
+		if done {
+			return
+		}
+	}
+	if n.Interval.Start > q.Start {
+		return
+	}
+	if n.Interval.End >= q.End {
+		done = fn(n.Elem)
+		if done {
+			return
+		}
+	}
+	if n.Right != nil && n.Right.Range.End >= q.End {
+		done = n.Right.doContaining(fn, q)
+	}
+	return
+}
+
+// DoContainedIn performs fn on all intervals stored in the tree that are contained in q, that
+// is intervals with a start no less than q.Start and an end no greater than q.End. Tree
+// traversal is pruned using the sort order of interval starts and the range of each subtree.
+// A boolean is returned indicating whether the Do traversal was interrupted by an FloatOperation
+// returning true. If fn alters stored intervals' end points, future tree operation behaviors
+// are undefined.
+func (t *FloatTree) DoContainedIn(fn FloatOperation, q FloatRange) bool {
+	if t.Root != nil && t.Root.Range.End >= q.Start && t.Root.Range.Start <= q.End {
+		return t.Root.doContainedIn(fn, q)
+	}
+	return false
+}
+
+func (n *FloatNode) doContainedIn(fn FloatOperation, q FloatRange) (done bool) {
+	if n.Left != nil && n.Interval.Start >= q.Start && n.Left.Range.End >= q.Start {
+		done = n.Left.doContainedIn(fn, q)
+		if done {
+			return
+		}
+	}
+	if n.Interval.Start > q.End {
+		return
+	}
+	if n.Interval.Start >= q.Start && n.Interval.End <= q.End {
+		done = fn(n.Elem)
+		if done {
+			return
+		}
+	}
+	if n.Right != nil && n.Right.Range.End >= q.Start {
+		done = n.Right.doContainedIn(fn, q)
+	}
+	return
+}
@@ -0,0 +1,364 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interval
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"gopkg.in/check.v1"
+
+	"github.com/biogo/store/llrb"
+)
+
+// Integrity checks
+
+// Is this tree a BST?
+func (t *FloatTree) isBST() bool {
+	if t == nil {
+		return true
+	}
+	return t.Root.isBST(t.Min(), t.Max())
+}
+
+// Are all the values in the BST rooted at x between min and max,
+// and does the same property hold for both subtrees?
+func (n *FloatNode) isBST(min, max FloatInterface) bool {
+	if n == nil {
+		return true
+	}
+	if n.Elem.Range().Start < min.Range().Start || n.Elem.Range().Start > max.Range().Start {
+		return false
+	}
+	return n.Left.isBST(min, n.Elem) || n.Right.isBST(n.Elem, max)
+}
+
+// Test BU and TD234 invariants.
+func (t *FloatTree) is23_234() bool {
+	if t == nil {
+		return true
+	}
+	return t.Root.is23_234()
+}
+func (n *FloatNode) is23_234() bool {
+	if n == nil {
+		return true
+	}
+	if Mode == BU23 {
+		// If the node has two children, only one of them may be red.
+		// The other must be black...
+		if (n.Left != nil) && (n.Right != nil) {
+			if n.Left.color() == llrb.Red && n.Right.color() == llrb.Red {
+				return false
+			}
+		}
+		// and the red node should really should be the left one.
+		if n.Right.color() == llrb.Red {
+			return false
+		}
+	} else if Mode == TD234 {
+		// This test is altered from that shown in the java since the trees
+		// shown in the paper do not conform to the test as it existed and the
+		// current situation does not break the 2-3-4 definition of the LLRB.
+		if n.Right.color() == llrb.Red && n.Left.color() == llrb.Black {
+			return false
+		}
+	} else {
+		panic("cannot reach")
+	}
+	if n.color() == llrb.Red && n.Left.color() == llrb.Red {
+		return false
+	}
+	return n.Left.is23_234() && n.Right.is23_234()
+}
+
+// Do all paths from root to leaf have same number of black edges?
+func (t *FloatTree) isBalanced() bool {
+	if t == nil {
+		return true
+	}
+	var black int // number of black links on path from root to min
+	for x := t.Root; x != nil; x = x.Left {
+		if x.color() == llrb.Black {
+			black++
+		}
+	}
+	return t.Root.isBalanced(black)
+}
+
+// Does every path from the root to a leaf have the given number
+// of black links?
+func (n *FloatNode) isBalanced(black int) bool {
+	if n == nil && black == 0 {
+		return true
+	} else if n == nil && black != 0 {
+		return false
+	}
+	if n.color() == llrb.Black {
+		black--
+	}
+	return n.Left.isBalanced(black) && n.Right.isBalanced(black)
+}
+
+// Does every node correctly annotate the range of its children.
+func (t *FloatTree) isRanged() bool {
+	if t == nil {
+		return true
+	}
+	return t.Root.isRanged()
+}
+func (n *FloatNode) isRanged() bool {
+	if n == nil {
+		return true
+	}
+	e, r := n.Elem, n.Range
+	m := n.bounding(e.Range())
+	return m.Start == r.Start && m.End == r.End &&
+		n.Left.isRanged() &&
+		n.Right.isRanged()
+}
+func (n *FloatNode) bounding(m FloatRange) FloatRange {
+	m.Start = floatMin(n.Elem.Range().Start, m.Start)
+	m.End = floatMax(n.Elem.Range().End, m.End)
+	if n.Left != nil {
+		m = n.Left.bounding(m)
+	}
+	if n.Right != nil {
+		m = n.Right.bounding(m)
+	}
+	return m
+}
+
+// Test helpers
+
+type floatOverlap struct {
+	start, end float64
+	id         uintptr
+}
+
+func (o *floatOverlap) Overlap(r FloatRange) bool {
+	return o.end > r.Start && o.start < r.End
+}
+func (o *floatOverlap) ID() uintptr       { return o.id }
+func (o *floatOverlap) Range() FloatRange { return FloatRange{o.start, o.end} }
+func (o *floatOverlap) String() string    { return fmt.Sprintf("[%g,%g)", o.start, o.end) }
+
+func floatMin(a, b float64) float64 { return math.Min(a, b) }
+func floatMax(a, b float64) float64 { return math.Max(a, b) }
+
+func (t *FloatTree) checkInvariants(c *check.C) {
+	failed := false
+	failed = failed || !c.Check(t.isBST(), check.Equals, true)
+	failed = failed || !c.Check(t.is23_234(), check.Equals, true)
+	failed = failed || !c.Check(t.isBalanced(), check.Equals, true)
+	failed = failed || !c.Check(t.isRanged(), check.Equals, true)
+	if failed {
+		c.Fatal("Cannot continue test: invariant contradiction")
+	}
+}
+
+// Tests
+
+func (s *S) TestFloatNilOperations(c *check.C) {
+	t := &FloatTree{}
+	c.Check(t.Min(), check.Equals, nil)
+	c.Check(t.Max(), check.Equals, nil)
+	if Mode == TD234 {
+		return
+	}
+	t.DeleteMin(false)
+	c.Check(*t, check.Equals, FloatTree{})
+	t.DeleteMax(false)
+	c.Check(*t, check.Equals, FloatTree{})
+}
+
+func (s *S) TestFloatInsertion(c *check.C) {
+	t := &FloatTree{}
+	for i := 0; i <= 1000; i++ {
+		start := float64(i) / 10
+		t.Insert(&floatOverlap{start: start, end: start + 0.5, id: uintptr(i)}, false)
+		c.Check(t.Len(), check.Equals, i+1)
+		t.checkInvariants(c)
+	}
+	c.Check(t.Min().Range().Start, check.Equals, 0.0)
+	c.Check(t.Max().Range().Start, check.Equals, 100.0)
+}
+
+func (s *S) TestFloatFastInsertion(c *check.C) {
+	t := &FloatTree{}
+	for i := 0; i <= 1000; i++ {
+		start := float64(i) / 10
+		t.Insert(&floatOverlap{start: start, end: start + 0.5, id: uintptr(i)}, true)
+	}
+	t.AdjustRanges()
+	t.checkInvariants(c)
+}
+
+func (s *S) TestFloatRandomInsertionDeletion(c *check.C) {
+	var (
+		count = 500
+		t     = &FloatTree{}
+		ivs   = make([]floatOverlap, count)
+	)
+	for i := range ivs {
+		s := rand.Float64() * 100
+		ivs[i] = floatOverlap{start: s, end: s + rand.Float64()*10, id: uintptr(i)}
+		c.Assert(t.Insert(&ivs[i], false), check.Equals, nil)
+		t.checkInvariants(c)
+	}
+	for i := range ivs {
+		o := t.Get(&ivs[i])
+		var found bool
+		for _, e := range o {
+			found = found || e == FloatInterface(&ivs[i])
+		}
+		c.Check(found, check.Equals, true)
+	}
+	for i := range ivs {
+		c.Assert(t.Delete(&ivs[i], false), check.Equals, nil)
+		c.Check(t.Len(), check.Equals, count-i-1)
+		t.checkInvariants(c)
+	}
+	c.Check(*t, check.Equals, FloatTree{})
+}
+
+func (s *S) TestFloatFloorCeil(c *check.C) {
+	t := &FloatTree{}
+	for i := 0; i <= 100; i += 2 {
+		t.Insert(&floatOverlap{start: float64(i), end: float64(i) + 1}, false)
+	}
+	for i := 1; i < 100; i += 2 {
+		l, err := t.Floor(&floatOverlap{start: float64(i) + 0.5})
+		c.Check(err, check.Equals, nil)
+		c.Check(l.Range().Start, check.Equals, float64(i-1))
+		u, err := t.Ceil(&floatOverlap{start: float64(i) + 0.5})
+		c.Check(err, check.Equals, nil)
+		c.Check(u.Range().Start, check.Equals, float64(i+1))
+	}
+	l, _ := t.Floor(&floatOverlap{start: -0.5})
+	c.Check(l, check.Equals, nil)
+	u, _ := t.Ceil(&floatOverlap{start: 100.5})
+	c.Check(u, check.Equals, nil)
+
+	_, err := t.Floor(&floatOverlap{start: math.NaN()})
+	c.Check(err, check.Equals, ErrNaNRange)
+	_, err = t.Ceil(&floatOverlap{start: math.NaN()})
+	c.Check(err, check.Equals, ErrNaNRange)
+}
+
+func (s *S) TestFloatDoMatching(c *check.C) {
+	t := &FloatTree{}
+	for i := 0; i < 100; i++ {
+		t.Insert(&floatOverlap{start: float64(i), end: float64(i) + 3, id: uintptr(i)}, false)
+	}
+	q := &floatOverlap{start: 10.5, end: 20}
+
+	var fwd, rev []float64
+	c.Check(t.DoMatching(func(e FloatInterface) (done bool) {
+		fwd = append(fwd, e.Range().Start)
+		return
+	}, q), check.Equals, false)
+	c.Check(t.DoMatchingReverse(func(e FloatInterface) (done bool) {
+		rev = append(rev, e.Range().Start)
+		return
+	}, q), check.Equals, false)
+	c.Assert(fwd, check.HasLen, 12)
+	c.Assert(rev, check.HasLen, len(fwd))
+	for i, v := range fwd {
+		c.Check(v, check.Equals, float64(8+i))
+		c.Check(rev[len(rev)-1-i], check.Equals, v)
+	}
+
+	var last FloatInterface
+	c.Check(t.DoMatchingReverse(func(e FloatInterface) (done bool) {
+		last = e
+		return true
+	}, q), check.Equals, true)
+	c.Check(last.Range(), check.Equals, FloatRange{19, 22})
+}
+
+func (s *S) TestFloatSpecialValues(c *check.C) {
+	var (
+		t   = &FloatTree{}
+		inf = math.Inf(1)
+		nan = math.NaN()
+	)
+	for i, iv := range []*floatOverlap{
+		{start: -inf, end: 0},
+		{start: 0, end: inf},
+		{start: -inf, end: inf},
+		{start: 5, end: 6},
+		{start: inf, end: inf},
+		{start: -inf, end: -inf},
+	} {
+		iv.id = uintptr(i)
+		c.Assert(t.Insert(iv, false), check.Equals, nil)
+		t.checkInvariants(c)
+	}
+	c.Check(t.Min().Range(), check.Equals, FloatRange{-inf, 0})
+	c.Check(t.Max().Range(), check.Equals, FloatRange{inf, inf})
+	c.Check(t.Root.Range, check.Equals, FloatRange{-inf, inf})
+	c.Check(len(t.Get(&floatOverlap{start: 5.5, end: 5.6})), check.Equals, 3)
+	c.Check(len(t.Get(&floatOverlap{start: -1, end: 0})), check.Equals, 2)
+
+	for _, iv := range []*floatOverlap{
+		{start: nan, end: 0},
+		{start: 0, end: nan},
+		{start: nan, end: nan},
+	} {
+		c.Check(t.Insert(iv, false), check.Equals, ErrNaNRange)
+		c.Check(t.Delete(iv, false), check.Equals, ErrNaNRange)
+	}
+	c.Check(t.Insert(&floatOverlap{start: inf, end: -inf}, false), check.Equals, ErrInvertedRange)
+	c.Check(t.Len(), check.Equals, 6)
+
+	c.Assert(t.Delete(&floatOverlap{start: -inf, end: inf, id: 2}, false), check.Equals, nil)
+	c.Check(t.Len(), check.Equals, 5)
+	t.checkInvariants(c)
+}
+
+func (s *S) TestFloatUpdate(c *check.C) {
+	t := &FloatTree{}
+	ivs := make([]floatOverlap, 100)
+	for i := range ivs {
+		ivs[i] = floatOverlap{start: float64(i), end: float64(i) + 1, id: uintptr(i)}
+		t.Insert(&ivs[i], false)
+	}
+	for i := range ivs {
+		old := ivs[i].Range()
+		ivs[i].start = rand.Float64() * 100
+		ivs[i].end = ivs[i].start + rand.Float64()
+		c.Assert(t.Update(&ivs[i], old), check.Equals, nil)
+		t.checkInvariants(c)
+	}
+	c.Check(t.Update(&ivs[0], FloatRange{-1, 0}), check.Equals, ErrStaleRange)
+	c.Check(t.Len(), check.Equals, len(ivs))
+}
+
+// Benchmarks
+
+func BenchmarkFloatInsert(b *testing.B) {
+	t := &FloatTree{}
+	for i := 0; i < b.N; i++ {
+		s := float64(b.N - i)
+		t.Insert(&floatOverlap{start: s, end: s + 10, id: uintptr(i)}, false)
+	}
+}
+
+func BenchmarkFloatGet(b *testing.B) {
+	b.StopTimer()
+	t := &FloatTree{}
+	for i := 0; i < b.N; i++ {
+		s := float64(b.N - i)
+		t.Insert(&floatOverlap{start: s, end: s + 10, id: uintptr(i)}, false)
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		s := float64(b.N - i)
+		t.Get(&floatOverlap{start: s, end: s + 10})
+	}
+}
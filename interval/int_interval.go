@@ -5,6 +5,8 @@
 package interval
 
 import (
+	"unsafe"
+
 	"github.com/biogo/store/llrb"
 )
 
@@ -175,6 +177,40 @@ func (t *IntTree) Len() int {
 	return t.Count
 }
 
+// Stats returns summary statistics for the IntTree.
+func (t *IntTree) Stats() llrb.Stats {
+	var s llrb.Stats
+	s.Height = t.Root.stats(&s, 0)
+	if s.Nodes != 0 {
+		s.MeanDepth /= float64(s.Nodes)
+	}
+	for n := t.Root; n != nil; n = n.Left {
+		if n.color() == llrb.Black {
+			s.BlackHeight++
+		}
+	}
+	s.Bytes = s.Nodes * int(unsafe.Sizeof(IntNode{}))
+	return s
+}
+
+// stats accumulates node counts and the sum of node depths into s, returning
+// the height of the subtree rooted at n.
+func (n *IntNode) stats(s *llrb.Stats, depth int) (height int) {
+	if n == nil {
+		return 0
+	}
+	s.Nodes++
+	s.MeanDepth += float64(depth)
+	if n.Left == nil && n.Right == nil {
+		s.Leaves++
+	}
+	height = n.Left.stats(s, depth+1)
+	if h := n.Right.stats(s, depth+1); h > height {
+		height = h
+	}
+	return height + 1
+}
+
 // Get returns a slice of IntInterfaces that overlap q in the IntTree according
 // to q.Overlap().
 func (t *IntTree) Get(q IntOverlapper) (o []IntInterface) {
@@ -399,6 +435,64 @@ func (n *IntNode) delete(m int, id uintptr, fast bool) (root *IntNode, d int) {
 	return
 }
 
+// Update relocates the element e in the IntTree, where old is the range of e when it was
+// last inserted or updated. The range augmentation of the tree is corrected. If no element
+// with the ID of e is stored with the range old, ErrStaleRange is returned and the tree is
+// not altered.
+func (t *IntTree) Update(e IntInterface, old IntRange) (err error) {
+	r := e.Range()
+	if r.Start > r.End {
+		return ErrInvertedRange
+	}
+	id := e.ID()
+	n := t.Root.find(old.Start, id)
+	if n == nil || n.Interval != old {
+		return ErrStaleRange
+	}
+	if r.Start == old.Start {
+		t.Root.update(e, r, id)
+		return
+	}
+
+	var d int
+	t.Root, d = t.Root.delete(old.Start, id, false)
+	t.Count += d
+	if t.Root != nil {
+		t.Root.Color = llrb.Black
+	}
+	return t.Insert(e, false)
+}
+
+// find returns the node holding the element with the given ID and start m.
+func (n *IntNode) find(m int, id uintptr) *IntNode {
+	for n != nil {
+		switch c := m - n.Interval.Start; {
+		case c == 0 && id == n.Elem.ID():
+			return n
+		case c < 0 || (c == 0 && id < n.Elem.ID()):
+			n = n.Left
+		default:
+			n = n.Right
+		}
+	}
+	return nil
+}
+
+// update replaces the element with the given ID and start r.Start with e, correcting
+// the range augmentation on the path to the element.
+func (n *IntNode) update(e IntInterface, r IntRange, id uintptr) {
+	switch c := r.Start - n.Interval.Start; {
+	case c == 0 && id == n.Elem.ID():
+		n.Elem = e
+		n.Interval = r
+	case c < 0 || (c == 0 && id < n.Elem.ID()):
+		n.Left.update(e, r, id)
+	default:
+		n.Right.update(e, r, id)
+	}
+	n.adjustRange()
+}
+
 // Return the left-most interval stored in the tree.
 func (t *IntTree) Min() IntInterface {
 	if t.Root == nil {
@@ -627,3 +721,72 @@ func (n *IntNode) doMatchReverse(fn IntOperation, q IntOverlapper) (done bool) {
 	}
 	return
 }
+
+// DoContaining performs fn on all intervals stored in the tree that contain q, that is intervals
+// with a start no greater than q.Start and an end no less than q.End. Tree traversal is pruned
+// using the sort order of interval starts and the range of each subtree. A boolean is returned
+// indicating whether the Do traversal was interrupted by an IntOperation returning true. If fn
+// alters stored intervals' end points, future tree operation behaviors are undefined.
+func (t *IntTree) DoContaining(fn IntOperation, q IntRange) bool {
+	if t.Root != nil && t.Root.Range.End >= q.End {
+		return t.Root.doContaining(fn, q)
+	}
+	return false
+}
+
+func (n *IntNode) doContaining(fn IntOperation, q IntRange) (done bool) {
+	if n.Left != nil && n.Left.Range.End >= q.End {
+		done = n.Left.doContaining(fn, q)
+		if done {
+			return
+		}
+	}
+	if n.Interval.Start > q.Start {
+		return
+	}
+	if n.Interval.End >= q.End {
+		done = fn(n.Elem)
+		if done {
+			return
+		}
+	}
+	if n.Right != nil && n.Right.Range.End >= q.End {
+		done = n.Right.doContaining(fn, q)
+	}
+	return
+}
+
+// DoContainedIn performs fn on all intervals stored in the tree that are contained in q, that
+// is intervals with a start no less than q.Start and an end no greater than q.End. Tree
+// traversal is pruned using the sort order of interval starts and the range of each subtree.
+// A boolean is returned indicating whether the Do traversal was interrupted by an IntOperation
+// returning true. If fn alters stored intervals' end points, future tree operation behaviors
+// are undefined.
+func (t *IntTree) DoContainedIn(fn IntOperation, q IntRange) bool {
+	if t.Root != nil && t.Root.Range.End >= q.Start && t.Root.Range.Start <= q.End {
+		return t.Root.doContainedIn(fn, q)
+	}
+	return false
+}
+
+func (n *IntNode) doContainedIn(fn IntOperation, q IntRange) (done bool) {
+	if n.Left != nil && n.Interval.Start >= q.Start && n.Left.Range.End >= q.Start {
+		done = n.Left.doContainedIn(fn, q)
+		if done {
+			return
+		}
+	}
+	if n.Interval.Start > q.End {
+		return
+	}
+	if n.Interval.Start >= q.Start && n.Interval.End <= q.End {
+		done = fn(n.Elem)
+		if done {
+			return
+		}
+	}
+	if n.Right != nil && n.Right.Range.End >= q.Start {
+		done = n.Right.doContainedIn(fn, q)
+	}
+	return
+}
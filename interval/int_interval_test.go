@@ -667,6 +667,125 @@ func (s *S) TestIntInsertBug(c *check.C) {
 	c.Check(t.Len(), check.Equals, 0, check.Commentf("Expected 0 entries, got %d", t.Len()))
 }
 
+func (s *S) TestIntDoContaining(c *check.C) {
+	var (
+		count, max = 1000, 1000
+		t          = &IntTree{}
+		ivs        []*intOverlap
+	)
+	for i := 0; i < count; i++ {
+		s := rand.Intn(max)
+		iv := &intOverlap{start: s, end: s + rand.Intn(100), id: uintptr(i)}
+		ivs = append(ivs, iv)
+		t.Insert(iv, false)
+	}
+	for i := 0; i < 100; i++ {
+		s := rand.Intn(max)
+		q := IntRange{s, s + rand.Intn(50)}
+
+		var containing, containedIn []IntInterface
+		for _, iv := range ivs {
+			if iv.start <= q.Start && iv.end >= q.End {
+				containing = append(containing, iv)
+			}
+			if iv.start >= q.Start && iv.end <= q.End {
+				containedIn = append(containedIn, iv)
+			}
+		}
+
+		var got []IntInterface
+		t.DoContaining(func(e IntInterface) (done bool) { got = append(got, e); return }, q)
+		c.Check(len(got), check.Equals, len(containing))
+		for _, e := range got {
+			r := e.Range()
+			c.Check(r.Start <= q.Start && r.End >= q.End, check.Equals, true)
+		}
+
+		got = nil
+		t.DoContainedIn(func(e IntInterface) (done bool) { got = append(got, e); return }, q)
+		c.Check(len(got), check.Equals, len(containedIn))
+		for _, e := range got {
+			r := e.Range()
+			c.Check(r.Start >= q.Start && r.End <= q.End, check.Equals, true)
+		}
+	}
+}
+
+func (s *S) TestIntUpdate(c *check.C) {
+	var (
+		count, max = 500, 1000
+		t          = &IntTree{}
+		ivs        = make([]intOverlap, count)
+	)
+	for i := range ivs {
+		s := rand.Intn(max)
+		ivs[i] = intOverlap{start: s, end: s + rand.Intn(100), id: uintptr(i)}
+		t.Insert(&ivs[i], false)
+	}
+	for i := 0; i < 2*count; i++ {
+		iv := &ivs[rand.Intn(count)]
+		old := iv.Range()
+		if i&1 == 0 {
+			iv.start = rand.Intn(max)
+		}
+		iv.end = iv.start + rand.Intn(100)
+
+		err := t.Update(iv, old)
+		c.Assert(err, check.Equals, nil)
+		c.Check(t.Len(), check.Equals, count)
+		failed := false
+		failed = failed || !c.Check(t.isBST(), check.Equals, true)
+		failed = failed || !c.Check(t.is23_234(), check.Equals, true)
+		failed = failed || !c.Check(t.isBalanced(), check.Equals, true)
+		failed = failed || !c.Check(t.isRanged(), check.Equals, true)
+		if failed {
+			if *printTree {
+				c.Logf("Failing tree: %s\n\n", t.Root.describeTree(false, true))
+			}
+			c.Fatal("Cannot continue test: invariant contradiction")
+		}
+	}
+	for i := range ivs {
+		if ivs[i].start == ivs[i].end {
+			continue
+		}
+		var found bool
+		t.DoMatching(func(e IntInterface) (done bool) {
+			found = e == IntInterface(&ivs[i])
+			return found
+		}, &ivs[i])
+		c.Check(found, check.Equals, true)
+	}
+
+	iv := &ivs[0]
+	c.Check(t.Update(iv, IntRange{iv.start, iv.end + 1}), check.Equals, ErrStaleRange)
+	c.Check(t.Update(&intOverlap{start: 0, end: 1, id: uintptr(count)}, IntRange{0, 1}), check.Equals, ErrStaleRange)
+	c.Check(t.Update(&intOverlap{start: 1, end: 0, id: 0}, iv.Range()), check.Equals, ErrInvertedRange)
+	c.Check(t.Len(), check.Equals, count)
+}
+
+func (s *S) TestIntStats(c *check.C) {
+	c.Check((&IntTree{}).Stats(), check.Equals, llrb.Stats{})
+
+	t := &IntTree{}
+	for i := 0; i < 1000; i++ {
+		t.Insert(&intOverlap{start: i, end: i + 10, id: uintptr(i)}, false)
+	}
+	st := t.Stats()
+	c.Check(st.Nodes, check.Equals, t.Len())
+	c.Check(st.Height <= 2*st.BlackHeight+1, check.Equals, true)
+	c.Check(st.MeanDepth < float64(st.Height), check.Equals, true)
+	c.Check(st.Leaves > 0, check.Equals, true)
+	c.Check(st.Bytes, check.Equals, st.Nodes*int(unsafe.Sizeof(IntNode{})))
+	var black int
+	for n := t.Root; n != nil; n = n.Left {
+		if n.color() == llrb.Black {
+			black++
+		}
+	}
+	c.Check(st.BlackHeight, check.Equals, black)
+}
+
 func (t *IntTree) dot(label string) string {
 	if t == nil {
 		return ""
@@ -0,0 +1,122 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interval
+
+// intIter performs an in-order traversal of an IntTree.
+type intIter struct {
+	stack []*IntNode
+}
+
+func newIntIter(t *IntTree) *intIter {
+	it := &intIter{}
+	it.pushLeft(t.Root)
+	return it
+}
+
+func (it *intIter) pushLeft(n *IntNode) {
+	for ; n != nil; n = n.Left {
+		it.stack = append(it.stack, n)
+	}
+}
+
+// peek returns the next node in the traversal without advancing.
+func (it *intIter) peek() *IntNode {
+	if len(it.stack) == 0 {
+		return nil
+	}
+	return it.stack[len(it.stack)-1]
+}
+
+// next advances the traversal.
+func (it *intIter) next() {
+	n := it.stack[len(it.stack)-1]
+	it.stack = it.stack[:len(it.stack)-1]
+	it.pushLeft(n.Right)
+}
+
+// JoinInt performs fn on every pair of overlapping intervals, x from a and y from b.
+// Intervals are treated as half-open, [Start, End), so an empty interval overlaps
+// only intervals that strictly contain its position. The trees are traversed
+// simultaneously in sort order, so the cost is O(n + m + k) for trees of size n and
+// m with k overlapping pairs. If fn returns true, the join is halted. A boolean is
+// returned indicating whether the join was interrupted by fn returning true.
+func JoinInt(a, b *IntTree, fn func(x, y IntInterface) (done bool)) bool {
+	return JoinIntFraction(a, b, 0, false, fn)
+}
+
+// JoinIntFraction performs fn on every pair of overlapping intervals, x from a and y
+// from b, where the overlap is at least the fraction f of the length of x. If
+// reciprocal is true, the overlap must also be at least the fraction f of the length
+// of y. JoinIntFraction is otherwise identical to JoinInt.
+func JoinIntFraction(a, b *IntTree, f float64, reciprocal bool, fn func(x, y IntInterface) (done bool)) bool {
+	var (
+		ia, ib = newIntIter(a), newIntIter(b)
+
+		activeA, activeB []*IntNode
+	)
+	report := func(x, y *IntNode) bool {
+		// Empty intervals are not retained in the active
+		// sets, so the only pairs that may not overlap
+		// involve an empty interval.
+		if x.Interval.End <= y.Interval.Start || y.Interval.End <= x.Interval.Start {
+			return false
+		}
+		if f > 0 {
+			o := float64(intMinOf(x.Interval.End, y.Interval.End) - intMaxOf(x.Interval.Start, y.Interval.Start))
+			if o < f*float64(x.Interval.End-x.Interval.Start) {
+				return false
+			}
+			if reciprocal && o < f*float64(y.Interval.End-y.Interval.Start) {
+				return false
+			}
+		}
+		return fn(x.Elem, y.Elem)
+	}
+	for {
+		na, nb := ia.peek(), ib.peek()
+		if na == nil && nb == nil {
+			return false
+		}
+		if nb == nil || (na != nil && na.Interval.Start <= nb.Interval.Start) {
+			ia.next()
+			activeB = intExpire(activeB, na.Interval.Start)
+			for _, y := range activeB {
+				if report(na, y) {
+					return true
+				}
+			}
+			if na.Interval.Start < na.Interval.End {
+				activeA = append(activeA, na)
+			}
+		} else {
+			ib.next()
+			activeA = intExpire(activeA, nb.Interval.Start)
+			for _, x := range activeA {
+				if report(x, nb) {
+					return true
+				}
+			}
+			if nb.Interval.Start < nb.Interval.End {
+				activeB = append(activeB, nb)
+			}
+		}
+	}
+}
+
+// intExpire removes nodes from active that end at or before pos. The order of
+// the remaining nodes is retained.
+func intExpire(active []*IntNode, pos int) []*IntNode {
+	var i int
+	for _, n := range active {
+		if n.Interval.End > pos {
+			active[i] = n
+			i++
+		}
+	}
+	for j := i; j < len(active); j++ {
+		active[j] = nil
+	}
+	return active[:i]
+}
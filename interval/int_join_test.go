@@ -0,0 +1,99 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interval
+
+import (
+	"math/rand"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+type intPair struct{ x, y uintptr }
+
+func (s *S) TestJoinInt(c *check.C) {
+	const max = 1000
+	random := func(n int) *IntTree {
+		t := &IntTree{}
+		for i := 0; i < n; i++ {
+			s := rand.Intn(max)
+			t.Insert(&intOverlap{start: s, end: s + rand.Intn(50), id: uintptr(i)}, false)
+		}
+		return t
+	}
+	for i := 0; i < 10; i++ {
+		a, b := random(200), random(300)
+		for _, test := range []struct {
+			f          float64
+			reciprocal bool
+		}{
+			{f: 0},
+			{f: 0.5},
+			{f: 0.5, reciprocal: true},
+			{f: 1},
+		} {
+			want := make(map[intPair]bool)
+			a.Do(func(x IntInterface) (done bool) {
+				xr := x.Range()
+				b.DoMatching(func(y IntInterface) (done bool) {
+					yr := y.Range()
+					o := float64(intMinOf(xr.End, yr.End) - intMaxOf(xr.Start, yr.Start))
+					if o < test.f*float64(xr.End-xr.Start) {
+						return
+					}
+					if test.reciprocal && o < test.f*float64(yr.End-yr.Start) {
+						return
+					}
+					want[intPair{x.ID(), y.ID()}] = true
+					return
+				}, halfOpen(xr))
+				return
+			})
+
+			got := make(map[intPair]bool)
+			JoinIntFraction(a, b, test.f, test.reciprocal, func(x, y IntInterface) (done bool) {
+				p := intPair{x.ID(), y.ID()}
+				c.Check(got[p], check.Equals, false, check.Commentf("duplicate pair %v", p))
+				got[p] = true
+				return
+			})
+			c.Check(got, check.DeepEquals, want)
+		}
+	}
+}
+
+func (s *S) TestJoinIntDone(c *check.C) {
+	a := makeIntTree(c, []*intOverlap{{start: 0, end: 10}, {start: 5, end: 15}})
+	b := makeIntTree(c, []*intOverlap{{start: 0, end: 10}, {start: 10, end: 20}})
+	var n int
+	c.Check(JoinInt(a, b, func(x, y IntInterface) (done bool) {
+		n++
+		return n == 2
+	}), check.Equals, true)
+	c.Check(n, check.Equals, 2)
+
+	n = 0
+	c.Check(JoinInt(a, b, func(x, y IntInterface) (done bool) {
+		n++
+		return
+	}), check.Equals, false)
+	c.Check(n, check.Equals, 3)
+	c.Check(JoinInt(a, &IntTree{}, func(x, y IntInterface) (done bool) {
+		panic("unexpected pair")
+	}), check.Equals, false)
+}
+
+func BenchmarkJoinInt(b *testing.B) {
+	b.StopTimer()
+	x, y := &IntTree{}, &IntTree{}
+	for i := 0; i < 1e5; i++ {
+		x.Insert(&intOverlap{start: i * 10, end: i*10 + 15, id: uintptr(i)}, false)
+		y.Insert(&intOverlap{start: i*10 + 5, end: i*10 + 12, id: uintptr(i)}, false)
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		JoinInt(x, y, func(_, _ IntInterface) (done bool) { return })
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interval
+
+import (
+	"fmt"
+)
+
+// An IntSetInterval is an interval produced by an IntTree set operation. Set operations
+// treat all intervals as half-open, [Start, End).
+type IntSetInterval struct {
+	Start, End int
+	UID        uintptr
+
+	// Sources holds the input intervals that contributed to the interval.
+	Sources []IntInterface
+}
+
+// Overlap returns whether the receiver overlaps b using half-open interval semantics.
+func (i *IntSetInterval) Overlap(b IntRange) bool { return i.End > b.Start && i.Start < b.End }
+
+// ID returns the ID of the receiver.
+func (i *IntSetInterval) ID() uintptr { return i.UID }
+
+// Range returns the range of the receiver.
+func (i *IntSetInterval) Range() IntRange { return IntRange{i.Start, i.End} }
+
+func (i *IntSetInterval) String() string { return fmt.Sprintf("[%d,%d)#%d", i.Start, i.End, i.UID) }
+
+// halfOpen is an IntOverlapper that matches using half-open interval semantics.
+type halfOpen IntRange
+
+func (q halfOpen) Overlap(b IntRange) bool { return q.End > b.Start && q.Start < b.End }
+
+// setBuilder accumulates the result of a set operation, assigning sequential IDs.
+type setBuilder struct {
+	t IntTree
+}
+
+func (b *setBuilder) add(start, end int, src []IntInterface) {
+	b.t.Insert(&IntSetInterval{Start: start, End: end, UID: uintptr(b.t.Count), Sources: src}, false)
+}
+
+// Merge returns a new IntTree holding the intervals of t merged into non-overlapping
+// intervals. Intervals are merged when the gap between them is no more than maxGap, so
+// a maxGap of zero merges overlapping and bookended intervals. The Sources of each
+// returned interval hold the intervals of t that were merged to form it, in sort order.
+func (t *IntTree) Merge(maxGap int) *IntTree {
+	var (
+		b          setBuilder
+		start, end int
+		src        []IntInterface
+	)
+	t.Do(func(e IntInterface) (done bool) {
+		r := e.Range()
+		if src != nil && r.Start-end <= maxGap {
+			if r.End > end {
+				end = r.End
+			}
+			src = append(src, e)
+			return
+		}
+		if src != nil {
+			b.add(start, end, src)
+		}
+		start, end = r.Start, r.End
+		src = []IntInterface{e}
+		return
+	})
+	if src != nil {
+		b.add(start, end, src)
+	}
+	return &b.t
+}
+
+// Intersect returns a new IntTree holding the intersections of each pair of overlapping
+// intervals from a and b. The Sources of each returned interval hold the interval from a
+// followed by the interval from b. Overlaps are found using the range augmentation of b,
+// so the cost is proportional to the number of intervals in a and the number of pairs
+// reported rather than to the product of the sizes of a and b.
+func Intersect(a, b *IntTree) *IntTree {
+	var o setBuilder
+	a.Do(func(x IntInterface) (done bool) {
+		xr := x.Range()
+		b.DoMatching(func(y IntInterface) (done bool) {
+			yr := y.Range()
+			o.add(intMaxOf(xr.Start, yr.Start), intMinOf(xr.End, yr.End), []IntInterface{x, y})
+			return
+		}, halfOpen(xr))
+		return
+	})
+	return &o.t
+}
+
+// Subtract returns a new IntTree holding the parts of intervals in a that are not
+// covered by any interval in b. Empty intervals in b are ignored. An interval in a
+// that is split by intervals in b results in more than one returned interval. The
+// Sources of each returned interval hold the interval from a that it derives from.
+func Subtract(a, b *IntTree) *IntTree {
+	var o setBuilder
+	a.Do(func(x IntInterface) (done bool) {
+		xr := x.Range()
+		pos := xr.Start
+		b.DoMatching(func(y IntInterface) (done bool) {
+			yr := y.Range()
+			if yr.Start == yr.End {
+				return
+			}
+			if yr.Start > pos {
+				o.add(pos, yr.Start, []IntInterface{x})
+			}
+			if yr.End > pos {
+				pos = yr.End
+			}
+			return pos >= xr.End
+		}, halfOpen(xr))
+		if pos < xr.End {
+			o.add(pos, xr.End, []IntInterface{x})
+		}
+		return
+	})
+	return &o.t
+}
+
+// Complement returns a new IntTree holding the intervals within the range that are not
+// covered by any interval in t. Empty intervals in t are ignored. The Sources of each
+// returned interval hold the intervals of t that flank it within the range.
+func (t *IntTree) Complement(within IntRange) *IntTree {
+	var (
+		o    setBuilder
+		pos  = within.Start
+		prev IntInterface
+	)
+	t.DoMatching(func(e IntInterface) (done bool) {
+		r := e.Range()
+		if r.Start == r.End {
+			return
+		}
+		if r.Start > pos {
+			var src []IntInterface
+			if prev != nil {
+				src = append(src, prev)
+			}
+			o.add(pos, r.Start, append(src, e))
+		}
+		if r.End > pos {
+			pos = r.End
+			prev = e
+		}
+		return pos >= within.End
+	}, halfOpen(within))
+	if pos < within.End {
+		var src []IntInterface
+		if prev != nil {
+			src = append(src, prev)
+		}
+		o.add(pos, within.End, src)
+	}
+	return &o.t
+}
+
+func intMinOf(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func intMaxOf(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,152 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interval
+
+import (
+	"math/rand"
+
+	"gopkg.in/check.v1"
+)
+
+func makeIntTree(c *check.C, ivs []*intOverlap) *IntTree {
+	t := &IntTree{}
+	for i, iv := range ivs {
+		iv.id = uintptr(i)
+		c.Assert(t.Insert(iv, false), check.Equals, nil)
+	}
+	return t
+}
+
+func setRanges(t *IntTree) (r []IntRange) {
+	t.Do(func(e IntInterface) (done bool) {
+		r = append(r, e.Range())
+		return
+	})
+	return
+}
+
+func (s *S) TestIntMerge(c *check.C) {
+	for _, test := range []struct {
+		ivs    []*intOverlap
+		maxGap int
+		want   []IntRange
+	}{
+		{
+			ivs:    nil,
+			maxGap: 0,
+			want:   nil,
+		},
+		{
+			ivs:    []*intOverlap{{start: 0, end: 2}, {start: 1, end: 3}, {start: 3, end: 4}, {start: 6, end: 8}},
+			maxGap: 0,
+			want:   []IntRange{{0, 4}, {6, 8}},
+		},
+		{
+			ivs:    []*intOverlap{{start: 0, end: 2}, {start: 1, end: 3}, {start: 3, end: 4}, {start: 6, end: 8}},
+			maxGap: -1,
+			want:   []IntRange{{0, 3}, {3, 4}, {6, 8}},
+		},
+		{
+			ivs:    []*intOverlap{{start: 0, end: 2}, {start: 1, end: 3}, {start: 3, end: 4}, {start: 6, end: 8}},
+			maxGap: 2,
+			want:   []IntRange{{0, 8}},
+		},
+		{
+			ivs:    []*intOverlap{{start: 0, end: 10}, {start: 1, end: 2}, {start: 11, end: 12}},
+			maxGap: 0,
+			want:   []IntRange{{0, 10}, {11, 12}},
+		},
+	} {
+		t := makeIntTree(c, test.ivs)
+		m := t.Merge(test.maxGap)
+		c.Check(m.isRanged(), check.Equals, true)
+		c.Check(setRanges(m), check.DeepEquals, test.want)
+		var n int
+		m.Do(func(e IntInterface) (done bool) {
+			n += len(e.(*IntSetInterval).Sources)
+			return
+		})
+		c.Check(n, check.Equals, t.Len())
+	}
+}
+
+func (s *S) TestIntIntersect(c *check.C) {
+	a := makeIntTree(c, []*intOverlap{{start: 0, end: 5}, {start: 10, end: 20}, {start: 30, end: 31}})
+	b := makeIntTree(c, []*intOverlap{{start: 3, end: 12}, {start: 15, end: 16}, {start: 20, end: 30}})
+	o := Intersect(a, b)
+	c.Check(setRanges(o), check.DeepEquals, []IntRange{{3, 5}, {10, 12}, {15, 16}})
+	o.Do(func(e IntInterface) (done bool) {
+		src := e.(*IntSetInterval).Sources
+		c.Assert(len(src), check.Equals, 2)
+		c.Check(src[0].Overlap(e.Range()), check.Equals, true)
+		c.Check(src[1].Overlap(e.Range()), check.Equals, true)
+		return
+	})
+}
+
+func (s *S) TestIntSubtract(c *check.C) {
+	a := makeIntTree(c, []*intOverlap{{start: 0, end: 20}, {start: 25, end: 30}, {start: 40, end: 50}})
+	b := makeIntTree(c, []*intOverlap{{start: 2, end: 4}, {start: 3, end: 6}, {start: 8, end: 8}, {start: 10, end: 12}, {start: 18, end: 26}, {start: 40, end: 50}})
+	o := Subtract(a, b)
+	c.Check(setRanges(o), check.DeepEquals, []IntRange{{0, 2}, {6, 10}, {12, 18}, {26, 30}})
+	o.Do(func(e IntInterface) (done bool) {
+		src := e.(*IntSetInterval).Sources
+		c.Assert(len(src), check.Equals, 1)
+		r := src[0].Range()
+		c.Check(r.Start <= e.Range().Start && e.Range().End <= r.End, check.Equals, true)
+		return
+	})
+}
+
+func (s *S) TestIntComplement(c *check.C) {
+	t := makeIntTree(c, []*intOverlap{{start: 2, end: 4}, {start: 3, end: 6}, {start: 8, end: 8}, {start: 10, end: 12}, {start: 18, end: 26}})
+	c.Check(setRanges(t.Complement(IntRange{0, 30})), check.DeepEquals, []IntRange{{0, 2}, {6, 10}, {12, 18}, {26, 30}})
+	c.Check(setRanges(t.Complement(IntRange{5, 20})), check.DeepEquals, []IntRange{{6, 10}, {12, 18}})
+	c.Check(setRanges(t.Complement(IntRange{3, 6})), check.DeepEquals, []IntRange(nil))
+	c.Check(setRanges((&IntTree{}).Complement(IntRange{3, 6})), check.DeepEquals, []IntRange{{3, 6}})
+}
+
+func (s *S) TestIntSetRandom(c *check.C) {
+	const max = 500
+	random := func() *IntTree {
+		t := &IntTree{}
+		for i := 0; i < 50; i++ {
+			s := rand.Intn(max)
+			t.Insert(&intOverlap{start: s, end: s + rand.Intn(20), id: uintptr(i)}, false)
+		}
+		return t
+	}
+	cover := func(t *IntTree) (m [max + 20]bool) {
+		t.Do(func(e IntInterface) (done bool) {
+			r := e.Range()
+			for i := r.Start; i < r.End; i++ {
+				m[i] = true
+			}
+			return
+		})
+		return
+	}
+	for i := 0; i < 20; i++ {
+		a, b := random(), random()
+		ca, cb := cover(a), cover(b)
+
+		var want [max + 20]bool
+		for j := range want {
+			want[j] = ca[j] && cb[j]
+		}
+		c.Check(cover(Intersect(a, b)), check.Equals, want)
+
+		for j := range want {
+			want[j] = ca[j] && !cb[j]
+		}
+		c.Check(cover(Subtract(a, b)), check.Equals, want)
+
+		for j := range want {
+			want[j] = !ca[j]
+		}
+		c.Check(cover(a.Complement(IntRange{0, len(want)})), check.Equals, want)
+		c.Check(cover(a.Merge(0)), check.Equals, ca)
+	}
+}
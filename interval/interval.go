@@ -28,6 +28,13 @@ func init() {
 // than the end value.
 var ErrInvertedRange = errors.New("interval: inverted range")
 
+// ErrNaNRange is returned if an interval is used with a NaN start or end value.
+var ErrNaNRange = errors.New("interval: NaN range")
+
+// ErrStaleRange is returned by Update if the element to be updated can not be found
+// in the tree using the provided old range.
+var ErrStaleRange = errors.New("interval: stale range")
+
 // An Overlapper can determine whether it overlaps a range.
 type Overlapper interface {
 	// Overlap returns a boolean indicating whether the receiver overlaps the parameter.
@@ -436,6 +443,74 @@ func (n *Node) delete(min Comparable, id uintptr, fast bool) (root *Node, d int)
 	return
 }
 
+// Update relocates the element e in the Tree, where old is the range of e when it was
+// last inserted or updated. The range augmentation of the tree is corrected. If no element
+// with the ID of e is found at old, ErrStaleRange is returned and the tree is not altered.
+func (t *Tree) Update(e Interface, old Range) (err error) {
+	if e.Start().Compare(e.End()) > 0 {
+		return ErrInvertedRange
+	}
+	id := e.ID()
+	n := t.Root.find(old.Start(), id)
+	if n == nil {
+		return ErrStaleRange
+	}
+	if e.Start().Compare(old.Start()) == 0 {
+		t.Root.update(e, old.Start(), id)
+		return
+	}
+
+	// The stored element may have been mutated, so
+	// ensure deletion sees the old start and end.
+	n.Elem = staleInterface{Interface: e, old: old}
+	var d int
+	t.Root, d = t.Root.delete(old.Start(), id, false)
+	t.Count += d
+	if t.Root != nil {
+		t.Root.Color = llrb.Black
+	}
+	return t.Insert(e, false)
+}
+
+// staleInterface is an Interface that reports the start and end of a previous range.
+type staleInterface struct {
+	Interface
+	old Range
+}
+
+func (s staleInterface) Start() Comparable { return s.old.Start() }
+func (s staleInterface) End() Comparable   { return s.old.End() }
+
+// find returns the node holding the element with the given ID and start m.
+// The element held by the returned node may have a start that differs from m.
+func (n *Node) find(m Comparable, id uintptr) *Node {
+	for n != nil {
+		if id == n.Elem.ID() {
+			return n
+		}
+		if c := m.Compare(n.Elem.Start()); c < 0 || (c == 0 && id < n.Elem.ID()) {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	return nil
+}
+
+// update replaces the element with the given ID and start m with e, correcting the
+// range augmentation on the path to the element.
+func (n *Node) update(e Interface, m Comparable, id uintptr) {
+	switch c := m.Compare(n.Elem.Start()); {
+	case id == n.Elem.ID():
+		n.Elem = e
+	case c < 0 || (c == 0 && id < n.Elem.ID()):
+		n.Left.update(e, m, id)
+	default:
+		n.Right.update(e, m, id)
+	}
+	n.adjustRange()
+}
+
 // Return the left-most interval stored in the tree.
 func (t *Tree) Min() Interface {
 	if t.Root == nil {
@@ -664,3 +739,72 @@ func (n *Node) doMatchReverse(fn Operation, q Overlapper) (done bool) {
 	}
 	return
 }
+
+// DoContaining performs fn on all intervals stored in the tree that contain q, that is intervals
+// with a start no greater than q.Start() and an end no less than q.End(). Tree traversal is pruned
+// using the sort order of interval starts and the range of each subtree. A boolean is returned
+// indicating whether the Do traversal was interrupted by an Operation returning true. If fn
+// alters stored intervals' sort relationships, future tree operation behaviors are undefined.
+func (t *Tree) DoContaining(fn Operation, q Range) bool {
+	if t.Root != nil && t.Root.Range.End().Compare(q.End()) >= 0 {
+		return t.Root.doContaining(fn, q)
+	}
+	return false
+}
+
+func (n *Node) doContaining(fn Operation, q Range) (done bool) {
+	if n.Left != nil && n.Left.Range.End().Compare(q.End()) >= 0 {
+		done = n.Left.doContaining(fn, q)
+		if done {
+			return
+		}
+	}
+	if n.Elem.Start().Compare(q.Start()) > 0 {
+		return
+	}
+	if n.Elem.End().Compare(q.End()) >= 0 {
+		done = fn(n.Elem)
+		if done {
+			return
+		}
+	}
+	if n.Right != nil && n.Right.Range.End().Compare(q.End()) >= 0 {
+		done = n.Right.doContaining(fn, q)
+	}
+	return
+}
+
+// DoContainedIn performs fn on all intervals stored in the tree that are contained in q, that
+// is intervals with a start no less than q.Start() and an end no greater than q.End(). Tree
+// traversal is pruned using the sort order of interval starts and the range of each subtree.
+// A boolean is returned indicating whether the Do traversal was interrupted by an Operation
+// returning true. If fn alters stored intervals' sort relationships, future tree operation
+// behaviors are undefined.
+func (t *Tree) DoContainedIn(fn Operation, q Range) bool {
+	if t.Root != nil && t.Root.Range.End().Compare(q.Start()) >= 0 && t.Root.Range.Start().Compare(q.End()) <= 0 {
+		return t.Root.doContainedIn(fn, q)
+	}
+	return false
+}
+
+func (n *Node) doContainedIn(fn Operation, q Range) (done bool) {
+	if n.Left != nil && n.Elem.Start().Compare(q.Start()) >= 0 && n.Left.Range.End().Compare(q.Start()) >= 0 {
+		done = n.Left.doContainedIn(fn, q)
+		if done {
+			return
+		}
+	}
+	if n.Elem.Start().Compare(q.End()) > 0 {
+		return
+	}
+	if n.Elem.Start().Compare(q.Start()) >= 0 && n.Elem.End().Compare(q.End()) <= 0 {
+		done = fn(n.Elem)
+		if done {
+			return
+		}
+	}
+	if n.Right != nil && n.Right.Range.End().Compare(q.Start()) >= 0 {
+		done = n.Right.doContainedIn(fn, q)
+	}
+	return
+}
@@ -801,6 +801,97 @@ func (s *S) TestInsertBug(c *check.C) {
 	c.Check(t.Len(), check.Equals, 0, check.Commentf("Expected 0 entries, got %d", t.Len()))
 }
 
+func (s *S) TestDoContaining(c *check.C) {
+	var (
+		count, max = 1000, 1000
+		t          = &Tree{}
+		ivs        []*overlap
+	)
+	for i := 0; i < count; i++ {
+		s := compInt(rand.Intn(max))
+		iv := &overlap{start: s, end: s + compInt(rand.Intn(100)), id: uintptr(i)}
+		ivs = append(ivs, iv)
+		t.Insert(iv, false)
+	}
+	for i := 0; i < 100; i++ {
+		s := compInt(rand.Intn(max))
+		q := &overlap{start: s, end: s + compInt(rand.Intn(50))}
+
+		var containing, containedIn []Interface
+		for _, iv := range ivs {
+			if iv.start <= q.start && iv.end >= q.end {
+				containing = append(containing, iv)
+			}
+			if iv.start >= q.start && iv.end <= q.end {
+				containedIn = append(containedIn, iv)
+			}
+		}
+
+		var got []Interface
+		t.DoContaining(func(e Interface) (done bool) { got = append(got, e); return }, q)
+		c.Check(len(got), check.Equals, len(containing))
+		for _, e := range got {
+			iv := e.(*overlap)
+			c.Check(iv.start <= q.start && iv.end >= q.end, check.Equals, true)
+		}
+
+		got = nil
+		t.DoContainedIn(func(e Interface) (done bool) { got = append(got, e); return }, q)
+		c.Check(len(got), check.Equals, len(containedIn))
+		for _, e := range got {
+			iv := e.(*overlap)
+			c.Check(iv.start >= q.start && iv.end <= q.end, check.Equals, true)
+		}
+	}
+}
+
+func (s *S) TestUpdate(c *check.C) {
+	var (
+		count, max = 500, 1000
+		t          = &Tree{}
+		ivs        = make([]overlap, count)
+	)
+	for i := range ivs {
+		s := compInt(rand.Intn(max))
+		ivs[i] = overlap{start: s, end: s + compInt(rand.Intn(100)), id: uintptr(i)}
+		t.Insert(&ivs[i], false)
+	}
+	for i := 0; i < 2*count; i++ {
+		iv := &ivs[rand.Intn(count)]
+		old := iv.NewMutable()
+		if i&1 == 0 {
+			iv.start = compInt(rand.Intn(max))
+		}
+		iv.end = iv.start + compInt(rand.Intn(100))
+
+		err := t.Update(iv, old)
+		c.Assert(err, check.Equals, nil)
+		c.Check(t.Len(), check.Equals, count)
+		failed := false
+		failed = failed || !c.Check(t.isBST(), check.Equals, true)
+		failed = failed || !c.Check(t.is23_234(), check.Equals, true)
+		failed = failed || !c.Check(t.isBalanced(), check.Equals, true)
+		failed = failed || !c.Check(t.isRanged(), check.Equals, true)
+		if failed {
+			if *printTree {
+				c.Logf("Failing tree: %s\n\n", t.Root.describeTree(false, true))
+			}
+			c.Fatal("Cannot continue test: invariant contradiction")
+		}
+	}
+	n := 0
+	t.Do(func(e Interface) (done bool) {
+		c.Check(e, check.Equals, Interface(&ivs[e.ID()]))
+		n++
+		return
+	})
+	c.Check(n, check.Equals, count)
+
+	c.Check(t.Update(&overlap{start: 0, end: 1, id: uintptr(count)}, &overlap{start: 0, end: 1}), check.Equals, ErrStaleRange)
+	c.Check(t.Update(&overlap{start: 1, end: 0, id: 0}, &ivs[0]), check.Equals, ErrInvertedRange)
+	c.Check(t.Len(), check.Equals, count)
+}
+
 func (t *Tree) dot(label string) string {
 	if t == nil {
 		return ""
@@ -0,0 +1,60 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"math"
+
+	"github.com/biogo/store/interval"
+)
+
+// DescribeFloat calculates the persistence landscape functions λₖ for the persistence
+// diagram described by pairs. fn is called for each k, in ascending order, with the
+// breakpoints of λₖ in ascending order of position, as described for DescribePiecewise.
+// Pairs with a death not greater than their birth do not contribute to the landscape.
+// DescribeFloat will panic if any pair has a NaN or infinite birth or death; essential
+// classes must be truncated before calculating their landscape.
+func DescribeFloat(pairs []Pair, fn func(k int, λₖ []Breakpoint)) {
+	p := make([]Pair, len(pairs))
+	for i, e := range pairs {
+		mustBeFinite(e)
+		p[i] = e
+	}
+	piecewise(p, fn)
+}
+
+// DescribeFloatTree calculates the persistence landscape functions λₖ for the interval
+// data in the provided float interval tree. fn is called as described for DescribeFloat.
+// DescribeFloatTree will panic if any interval in the tree has an infinite end point.
+func DescribeFloatTree(it *interval.FloatTree, fn func(k int, λₖ []Breakpoint)) {
+	if it == nil || it.Len() == 0 {
+		return
+	}
+	p := make([]Pair, 0, it.Len())
+	it.Do(func(iv interval.FloatInterface) (done bool) {
+		r := iv.Range()
+		e := Pair{Birth: r.Start, Death: r.End}
+		mustBeFinite(e)
+		p = append(p, e)
+		return
+	})
+	piecewise(p, fn)
+}
+
+// FloatLandscape returns the persistence landscape functions λₖ for the persistence
+// diagram described by pairs as slices of breakpoints indexed by k.
+func FloatLandscape(pairs []Pair) [][]Breakpoint {
+	var λ [][]Breakpoint
+	DescribeFloat(pairs, func(_ int, λₖ []Breakpoint) {
+		λ = append(λ, append([]Breakpoint(nil), λₖ...))
+	})
+	return λ
+}
+
+func mustBeFinite(p Pair) {
+	if math.IsNaN(p.Birth) || math.IsNaN(p.Death) || math.IsInf(p.Birth, 0) || math.IsInf(p.Death, 0) {
+		panic("landscape: non-finite pair")
+	}
+}
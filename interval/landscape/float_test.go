@@ -0,0 +1,114 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"gopkg.in/check.v1"
+
+	"github.com/biogo/store/interval"
+)
+
+type fiv struct {
+	Start, End float64
+	UID        uintptr
+}
+
+func (i fiv) Overlap(b interval.FloatRange) bool { return i.End > b.Start && i.Start < b.End }
+func (i fiv) ID() uintptr                        { return i.UID }
+func (i fiv) Range() interval.FloatRange         { return interval.FloatRange{Start: i.Start, End: i.End} }
+
+var floatTests = []struct {
+	pairs  []Pair
+	expect [][]Breakpoint
+}{
+	{
+		pairs:  nil,
+		expect: nil,
+	},
+	{
+		pairs:  []Pair{{Birth: 1, Death: 1}, {Birth: 2, Death: 1}},
+		expect: nil,
+	},
+	{
+		pairs: []Pair{{Birth: 0, Death: 1}, {Birth: 0.5, Death: 1.5}},
+		expect: [][]Breakpoint{
+			{{0, 0}, {0.5, 0.5}, {0.75, 0.25}, {1, 0.5}, {1.5, 0}},
+			{{0.5, 0}, {0.75, 0.25}, {1, 0}},
+		},
+	},
+	{
+		pairs: []Pair{{Birth: 1, Death: 2}, {Birth: 0, Death: 4}},
+		expect: [][]Breakpoint{
+			{{0, 0}, {2, 2}, {4, 0}},
+			{{1, 0}, {1.5, 0.5}, {2, 0}},
+		},
+	},
+	{
+		pairs: []Pair{{Birth: -1, Death: 0.5}, {Birth: 2, Death: 3}, {Birth: 0.5, Death: 1.5}},
+		expect: [][]Breakpoint{
+			{{-1, 0}, {-0.25, 0.75}, {0.5, 0}, {1, 0.5}, {1.5, 0}, {2, 0}, {2.5, 0.5}, {3, 0}},
+		},
+	},
+	{
+		pairs: []Pair{{Birth: 0, Death: 2}, {Birth: 0, Death: 2}, {Birth: 0, Death: 2}},
+		expect: [][]Breakpoint{
+			{{0, 0}, {1, 1}, {2, 0}},
+			{{0, 0}, {1, 1}, {2, 0}},
+			{{0, 0}, {1, 1}, {2, 0}},
+		},
+	},
+}
+
+func (s *S) TestDescribeFloat(c *check.C) {
+	for i, t := range floatTests {
+		c.Check(FloatLandscape(t.pairs), check.DeepEquals, t.expect, check.Commentf("Test %d: %v", i, t.pairs))
+
+		var (
+			it interval.FloatTree
+			λ  [][]Breakpoint
+		)
+		for id, p := range t.pairs {
+			if p.Birth <= p.Death {
+				c.Assert(it.Insert(fiv{Start: p.Birth, End: p.Death, UID: uintptr(id)}, false), check.Equals, nil)
+			}
+		}
+		DescribeFloatTree(&it, func(_ int, l []Breakpoint) {
+			λ = append(λ, append([]Breakpoint(nil), l...))
+		})
+		c.Check(λ, check.DeepEquals, t.expect, check.Commentf("Test %d: %v", i, t.pairs))
+	}
+	for _, p := range []Pair{{Birth: 0, Death: math.Inf(1)}, {Birth: math.NaN(), Death: 1}} {
+		c.Check(func() { FloatLandscape([]Pair{p}) }, check.PanicMatches, "landscape: non-finite pair")
+	}
+}
+
+func (s *S) TestDescribeFloatRandom(c *check.C) {
+	for n := 0; n < 20; n++ {
+		pairs := make([]Pair, 30)
+		for i := range pairs {
+			b := rand.Float64() * 10
+			pairs[i] = Pair{Birth: b, Death: b + rand.Float64()*3}
+		}
+		λ := FloatLandscape(pairs)
+		for i := 0; i < 100; i++ {
+			t := rand.Float64()*14 - 1
+			var tents []float64
+			for _, p := range pairs {
+				tents = append(tents, math.Max(0, math.Min(t-p.Birth, p.Death-t)))
+			}
+			sort.Sort(sort.Reverse(sort.Float64Slice(tents)))
+			for k, l := range λ {
+				c.Check(math.Abs(at(l, t)-tents[k]) < 1e-12, check.Equals, true, check.Commentf("λ%d(%v)", k, t))
+			}
+			if len(λ) < len(tents) {
+				c.Check(tents[len(λ)], check.Equals, 0.0)
+			}
+		}
+	}
+}
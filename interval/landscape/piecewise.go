@@ -0,0 +1,148 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"sort"
+
+	"github.com/biogo/store/interval"
+)
+
+// A Breakpoint is a point at which the slope of a piecewise-linear λₖ function changes.
+type Breakpoint struct {
+	T      float64 // Position of the breakpoint.
+	Lambda float64 // Value of λₖ(T).
+}
+
+// DescribePiecewise calculates the persistence landscape functions λₖ for the interval
+// data in the provided Interface. fn is called for each k, in ascending order, with the
+// breakpoints of λₖ in ascending order of position. λₖ is linear between consecutive
+// breakpoints and zero before the first and after the last breakpoint. Breakpoints may
+// fall at half-integer positions where tent functions intersect. The cost of the
+// calculation depends on the number of intervals rather than the span of the data.
+func DescribePiecewise(data Interface, fn func(k int, λₖ []Breakpoint)) {
+	piecewise(pairsOf(data), fn)
+}
+
+// DescribeTreePiecewise calculates the persistence landscape functions λₖ for the interval
+// data in the provided interval tree. fn is called as described for DescribePiecewise.
+func DescribeTreePiecewise(it *interval.IntTree, fn func(k int, λₖ []Breakpoint)) {
+	piecewise(treePairs(it), fn)
+}
+
+// A Pair is a birth-death pair of a persistence diagram. The pair describes the
+// support of a tent function of a persistence landscape.
+type Pair struct {
+	Birth, Death float64
+}
+
+// pairsOf returns the birth-death pairs of the intervals in data.
+func pairsOf(data Interface) []Pair {
+	if data == nil || data.Len() == 0 {
+		return nil
+	}
+	p := make([]Pair, data.Len())
+	for i := range p {
+		r := data.Item(i)
+		p[i] = Pair{Birth: float64(r.Start), Death: float64(r.End)}
+	}
+	return p
+}
+
+// treePairs returns the birth-death pairs of the intervals in it.
+func treePairs(it *interval.IntTree) []Pair {
+	if it == nil || it.Len() == 0 {
+		return nil
+	}
+	p := make([]Pair, 0, it.Len())
+	it.Do(func(iv interval.IntInterface) (done bool) {
+		r := iv.Range()
+		p = append(p, Pair{Birth: float64(r.Start), Death: float64(r.End)})
+		return
+	})
+	return p
+}
+
+// byBirthDeath sorts pairs by ascending birth and descending death.
+type byBirthDeath []Pair
+
+func (p byBirthDeath) Len() int           { return len(p) }
+func (p byBirthDeath) Less(i, j int) bool { return pairLess(p[i], p[j]) }
+func (p byBirthDeath) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+func pairLess(a, b Pair) bool {
+	if a.Birth != b.Birth {
+		return a.Birth < b.Birth
+	}
+	return a.Death > b.Death
+}
+
+// piecewise calculates the exact persistence landscape of the pairs in p, which is
+// altered by the call. Pairs with a death not greater than their birth are ignored.
+//
+// The algorithm is described in 'A persistence landscapes toolbox for topological
+// statistics.' P. Bubenik and P. Dłotko doi:10.1016/j.jsc.2016.03.009
+func piecewise(p []Pair, fn func(k int, λₖ []Breakpoint)) {
+	var n int
+	for _, e := range p {
+		if e.Death > e.Birth {
+			p[n] = e
+			n++
+		}
+	}
+	a := byBirthDeath(p[:n])
+	sort.Sort(a)
+
+	var λ []Breakpoint
+	for k := 0; len(a) != 0; k++ {
+		λ = λ[:0]
+		cur := a[0]
+		a = append(a[:0], a[1:]...)
+		λ = append(λ, Breakpoint{T: cur.Birth}, peak(cur))
+		for i := 0; ; {
+			// Find the first pair at or after i that
+			// extends beyond the current pair.
+			for i < len(a) && a[i].Death <= cur.Death {
+				i++
+			}
+			if i == len(a) {
+				λ = append(λ, Breakpoint{T: cur.Death})
+				break
+			}
+			next := a[i]
+			a = append(a[:i], a[i+1:]...)
+			switch {
+			case next.Birth > cur.Death:
+				λ = append(λ, Breakpoint{T: cur.Death}, Breakpoint{T: next.Birth})
+			case next.Birth == cur.Death:
+				λ = append(λ, Breakpoint{T: cur.Death})
+			default:
+				λ = append(λ, Breakpoint{
+					T:      (next.Birth + cur.Death) / 2,
+					Lambda: (cur.Death - next.Birth) / 2,
+				})
+
+				// The part of the next tent below the current
+				// tent contributes to lower layers.
+				low := Pair{Birth: next.Birth, Death: cur.Death}
+				j := sort.Search(len(a), func(j int) bool { return !pairLess(a[j], low) })
+				a = append(a, Pair{})
+				copy(a[j+1:], a[j:])
+				a[j] = low
+				if j <= i {
+					i++
+				}
+			}
+			λ = append(λ, peak(next))
+			cur = next
+		}
+		fn(k, λ)
+	}
+}
+
+// peak returns the breakpoint at the peak of the tent function of p.
+func peak(p Pair) Breakpoint {
+	return Breakpoint{T: (p.Birth + p.Death) / 2, Lambda: (p.Death - p.Birth) / 2}
+}
@@ -0,0 +1,92 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"math/rand"
+
+	"gopkg.in/check.v1"
+
+	"github.com/biogo/store/interval"
+)
+
+// at returns the value of the piecewise-linear function described by bp at t.
+func at(bp []Breakpoint, t float64) float64 {
+	for i := 1; i < len(bp); i++ {
+		a, b := bp[i-1], bp[i]
+		if a.T <= t && t <= b.T {
+			if a.T == b.T {
+				return a.Lambda
+			}
+			return a.Lambda + (b.Lambda-a.Lambda)*(t-a.T)/(b.T-a.T)
+		}
+	}
+	return 0
+}
+
+func piecewiseOf(data ivs) [][]Breakpoint {
+	var λ [][]Breakpoint
+	DescribePiecewise(data, func(k int, l []Breakpoint) {
+		if k != len(λ) {
+			panic("landscape: k out of order")
+		}
+		λ = append(λ, append([]Breakpoint(nil), l...))
+	})
+	return λ
+}
+
+func (s *S) TestDescribePiecewise(c *check.C) {
+	c.Check(piecewiseOf(ivs{{Start: 1, End: 5}, {Start: 3, End: 9}, {Start: 4, End: 4}}), check.DeepEquals, [][]Breakpoint{
+		{{1, 0}, {3, 2}, {4, 1}, {6, 3}, {9, 0}},
+		{{3, 0}, {4, 1}, {5, 0}},
+	})
+	c.Check(piecewiseOf(ivs{{Start: 1, End: 5}, {Start: 5, End: 9}, {Start: 10, End: 12}}), check.DeepEquals, [][]Breakpoint{
+		{{1, 0}, {3, 2}, {5, 0}, {7, 2}, {9, 0}, {10, 0}, {11, 1}, {12, 0}},
+	})
+
+	for i, t := range testData {
+		λ := piecewiseOf(append(ivs(nil), t.ivs...))
+		for _, e := range t.expect {
+			for k, v := range e.l {
+				var got float64
+				if k < len(λ) {
+					got = at(λ[k], float64(e.t))
+				}
+				c.Check(got, check.Equals, float64(v), check.Commentf("Test %d: λ%d(%d)", i, k, e.t))
+			}
+		}
+	}
+}
+
+func (s *S) TestDescribePiecewiseRandom(c *check.C) {
+	for n := 0; n < 20; n++ {
+		var (
+			data ivs
+			it   interval.IntTree
+		)
+		for i := 0; i < 50; i++ {
+			s := rand.Intn(200)
+			e := iv{Start: s, End: s + rand.Intn(50), UID: uintptr(i)}
+			data = append(data, e)
+			it.Insert(e, false)
+		}
+		λ := piecewiseOf(append(ivs(nil), data...))
+		var tree [][]Breakpoint
+		DescribeTreePiecewise(&it, func(_ int, l []Breakpoint) {
+			tree = append(tree, append([]Breakpoint(nil), l...))
+		})
+		c.Check(tree, check.DeepEquals, λ)
+
+		Describe(data, func(pos int, l []int) {
+			for k := range λ {
+				var want float64
+				if k < len(l) {
+					want = float64(l[k])
+				}
+				c.Check(at(λ[k], float64(pos)), check.Equals, want, check.Commentf("λ%d(%d)", k, pos))
+			}
+		})
+	}
+}
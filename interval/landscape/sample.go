@@ -0,0 +1,223 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// The landscape WeightedInterface allows arbitrary collections of weighted intervals
+// to be described as a persistence landscape.
+type WeightedInterface interface {
+	Interface
+	// Weight returns the non-negative scale applied to the tent
+	// function of the ith interval.
+	Weight(int) float64
+}
+
+type weightedRange struct {
+	start, end int
+	weight     float64
+}
+
+type weightedHeap []weightedRange
+
+func (e weightedHeap) Len() int              { return len(e) }
+func (e weightedHeap) Less(i, j int) bool    { return e[i].end < e[j].end }
+func (e weightedHeap) Swap(i, j int)         { e[i], e[j] = e[j], e[i] }
+func (e *weightedHeap) Push(x interface{})   { *e = append(*e, x.(weightedRange)) }
+func (e *weightedHeap) Pop() (i interface{}) { i, *e = (*e)[len(*e)-1], (*e)[:len(*e)-1]; return i }
+
+// DescribeWeighted calculates the persistence landscape functions λₖ for the weighted
+// interval data in the provided WeightedInterface, where the tent function of each
+// interval is scaled by its weight. fn is called for each position t of the span of
+// the interval data with the values for t and the k λ functions at t. Explicit zero
+// values for a λₖ(t) are included only at the end points of intervals in the span.
+func DescribeWeighted(data WeightedInterface, fn func(t int, λₜ []float64)) {
+	if data == nil || data.Len() == 0 {
+		return
+	}
+	sort.Sort(data)
+	var (
+		h   weightedHeap
+		t   = data.Item(0).Start
+		end = t
+		l   []float64
+	)
+	for i := 0; i < data.Len(); i++ {
+		if e := data.Item(i).End; e > end {
+			end = e
+		}
+	}
+	for i := 0; t <= end; t++ {
+		for ; i < data.Len() && data.Item(i).Start <= t; i++ {
+			r := data.Item(i)
+			heap.Push(&h, weightedRange{start: r.Start, end: r.End, weight: data.Weight(i)})
+		}
+		for len(h) > 0 && h[0].end <= t {
+			heap.Pop(&h)
+			l = append(l, 0)
+		}
+		for _, iv := range h {
+			if iv.start == t {
+				l = append(l, 0)
+			}
+			if v := max(0, min(t-iv.start, iv.end-t)); v > 0 {
+				l = append(l, float64(v)*iv.weight)
+			}
+		}
+		sort.Sort(sort.Reverse(sort.Float64Slice(l)))
+		fn(t, l)
+		l = l[:0]
+	}
+}
+
+// Sampled is a persistence landscape sampled at integer positions.
+type Sampled struct {
+	// Start is the first sampled position.
+	Start int
+
+	// Lambda holds the sampled λₖ functions, with λₖ(t) held
+	// in Lambda[k][t-Start]. Lambda[k] may be shorter than the
+	// span of the landscape, with λₖ(t) zero beyond its end.
+	Lambda [][]float64
+}
+
+// Sample returns the persistence landscape of the interval data in the provided Interface.
+func Sample(data Interface) *Sampled {
+	s := &Sampled{}
+	first := true
+	Describe(data, func(t int, λₜ []int) {
+		if first {
+			s.Start = t
+			first = false
+		}
+		for k, v := range λₜ {
+			s.set(k, t, float64(v))
+		}
+	})
+	return s
+}
+
+// SampleWeighted returns the persistence landscape of the weighted interval data in the
+// provided WeightedInterface.
+func SampleWeighted(data WeightedInterface) *Sampled {
+	s := &Sampled{}
+	first := true
+	DescribeWeighted(data, func(t int, λₜ []float64) {
+		if first {
+			s.Start = t
+			first = false
+		}
+		for k, v := range λₜ {
+			s.set(k, t, v)
+		}
+	})
+	return s
+}
+
+// set sets λₖ(t) to v, extending the landscape as required. The position t must
+// not be less than s.Start.
+func (s *Sampled) set(k, t int, v float64) {
+	for len(s.Lambda) <= k {
+		s.Lambda = append(s.Lambda, nil)
+	}
+	i := t - s.Start
+	for len(s.Lambda[k]) <= i {
+		s.Lambda[k] = append(s.Lambda[k], 0)
+	}
+	s.Lambda[k][i] = v
+}
+
+// At returns λₖ(t). Positions and layers outside the sampled landscape return zero.
+func (s *Sampled) At(k, t int) float64 {
+	if k < 0 || k >= len(s.Lambda) {
+		return 0
+	}
+	i := t - s.Start
+	if i < 0 || i >= len(s.Lambda[k]) {
+		return 0
+	}
+	return s.Lambda[k][i]
+}
+
+// span returns the half-open span of positions holding values in the provided
+// landscapes and the maximum number of layers. If no landscape holds values, ok
+// is returned false.
+func span(ls ...*Sampled) (start, end, layers int, ok bool) {
+	for _, l := range ls {
+		if len(l.Lambda) > layers {
+			layers = len(l.Lambda)
+		}
+		for _, λ := range l.Lambda {
+			if len(λ) == 0 {
+				continue
+			}
+			if !ok || l.Start < start {
+				start = l.Start
+			}
+			if !ok || l.Start+len(λ) > end {
+				end = l.Start + len(λ)
+			}
+			ok = true
+		}
+	}
+	return start, end, layers, ok
+}
+
+// Mean returns the mean of the provided persistence landscapes, λ̄ₖ(t) = 1/n Σ λₖ(t).
+// Mean returns nil if no landscape is provided.
+func Mean(ls ...*Sampled) *Sampled {
+	if len(ls) == 0 {
+		return nil
+	}
+	start, end, layers, ok := span(ls...)
+	m := &Sampled{Start: start}
+	if !ok {
+		return m
+	}
+	n := float64(len(ls))
+	m.Lambda = make([][]float64, layers)
+	for k := range m.Lambda {
+		m.Lambda[k] = make([]float64, end-start)
+		for _, l := range ls {
+			if k >= len(l.Lambda) {
+				continue
+			}
+			for i, v := range l.Lambda[k] {
+				m.Lambda[k][l.Start+i-start] += v / n
+			}
+		}
+	}
+	return m
+}
+
+// Distance returns the Lᵖ distance between the persistence landscapes a and b, calculated
+// as the pth root of the sum of |aₖ(t) - bₖ(t)|ᵖ over all layers k and sampled positions t.
+// If p is +Inf, the maximum absolute difference is returned. Distance panics if p is less
+// than 1.
+func Distance(a, b *Sampled, p float64) float64 {
+	if !(p >= 1) {
+		panic("landscape: invalid norm")
+	}
+	start, end, layers, _ := span(a, b)
+	var d float64
+	for k := 0; k < layers; k++ {
+		for t := start; t < end; t++ {
+			v := math.Abs(a.At(k, t) - b.At(k, t))
+			if math.IsInf(p, 1) {
+				d = math.Max(d, v)
+			} else {
+				d += math.Pow(v, p)
+			}
+		}
+	}
+	if math.IsInf(p, 1) {
+		return d
+	}
+	return math.Pow(d, 1/p)
+}
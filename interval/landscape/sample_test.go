@@ -0,0 +1,93 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"math"
+
+	"gopkg.in/check.v1"
+)
+
+type weightedIvs struct {
+	ivs
+	w []float64
+}
+
+func (s weightedIvs) Less(i, j int) bool { return s.ivs[i].Start < s.ivs[j].Start }
+func (s weightedIvs) Swap(i, j int) {
+	s.ivs[i], s.ivs[j] = s.ivs[j], s.ivs[i]
+	s.w[i], s.w[j] = s.w[j], s.w[i]
+}
+func (s weightedIvs) Weight(i int) float64 { return s.w[i] }
+
+func uniform(data []iv, w float64) weightedIvs {
+	s := weightedIvs{ivs: append(ivs(nil), data...), w: make([]float64, len(data))}
+	for i := range s.w {
+		s.w[i] = w
+	}
+	return s
+}
+
+func (s *S) TestDescribeWeighted(c *check.C) {
+	for i, t := range testData {
+		for _, w := range []float64{1, 0.5, 3} {
+			var r []lr
+			DescribeWeighted(uniform(t.ivs, w), func(pos int, l []float64) {
+				if len(l) > 0 {
+					il := make([]int, len(l))
+					for j, v := range l {
+						il[j] = int(v / w)
+					}
+					r = append(r, lr{pos, il})
+				}
+			})
+			c.Check(r, check.DeepEquals, t.expect, check.Commentf("Test %d: %v weight %v", i, t.ivs, w))
+		}
+	}
+
+	// Weighting reorders layers.
+	data := weightedIvs{ivs: ivs{{Start: 0, End: 10}, {Start: 1, End: 5}}, w: []float64{0.1, 1}}
+	var got []float64
+	DescribeWeighted(data, func(pos int, l []float64) {
+		if pos == 3 {
+			got = append(got, l...)
+		}
+	})
+	c.Check(got, check.DeepEquals, []float64{2, 0.30000000000000004})
+}
+
+func (s *S) TestSample(c *check.C) {
+	for i, t := range testData {
+		sam := Sample(append(ivs(nil), t.ivs...))
+		for _, e := range t.expect {
+			for k, v := range e.l {
+				c.Check(sam.At(k, e.t), check.Equals, float64(v), check.Commentf("Test %d: λ%d(%d)", i, k, e.t))
+			}
+			c.Check(sam.At(len(e.l), e.t), check.Equals, 0.0)
+		}
+		c.Check(sam.At(0, -100), check.Equals, 0.0)
+		c.Check(sam.At(-1, 0), check.Equals, 0.0)
+
+		w := SampleWeighted(uniform(t.ivs, 2))
+		c.Check(Distance(Mean(w), w, 1), check.Equals, 0.0)
+		c.Check(Distance(Mean(sam, sam, sam), sam, 2), check.Equals, 0.0)
+		c.Check(Distance(Mean(sam, w), SampleWeighted(uniform(t.ivs, 1.5)), math.Inf(1)), check.Equals, 0.0)
+	}
+	c.Check(Mean(), check.IsNil)
+}
+
+func (s *S) TestDistance(c *check.C) {
+	a := Sample(ivs{{Start: 0, End: 4}}) // λ₀ = 0 1 2 1 0 at 0..4.
+	b := Sample(ivs{{Start: 2, End: 6}}) // λ₀ = 0 1 2 1 0 at 2..6.
+	e := Sample(ivs(nil))                // Empty landscape.
+	m := Mean(a, b)                      // λ₀ = 0 .5 1 1 1 .5 0 at 0..6.
+	c.Check(m.Lambda, check.DeepEquals, [][]float64{{0, 0.5, 1, 1, 1, 0.5, 0}})
+	c.Check(Distance(a, e, 1), check.Equals, 4.0)
+	c.Check(Distance(e, a, 2), check.Equals, math.Sqrt(6))
+	c.Check(Distance(a, b, 1), check.Equals, 6.0)
+	c.Check(Distance(a, b, math.Inf(1)), check.Equals, 2.0)
+	c.Check(Distance(a, a, 3), check.Equals, 0.0)
+	c.Check(func() { Distance(a, b, 0.5) }, check.PanicMatches, "landscape: invalid norm")
+}
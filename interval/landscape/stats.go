@@ -0,0 +1,134 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"math"
+	"sort"
+
+	"github.com/biogo/store/interval"
+)
+
+// Norm returns the Lp norm of each persistence landscape function λₖ of the interval
+// data in the provided Interface, indexed by k. The norms are calculated exactly over
+// the real line from the interval end points. Norm will panic if p is less than 1.
+func Norm(data Interface, p float64) []float64 {
+	return norm(pairsOf(data), p)
+}
+
+// NormTree returns the Lp norm of each persistence landscape function λₖ of the interval
+// data in the provided interval tree, as described for Norm.
+func NormTree(it *interval.IntTree, p float64) []float64 {
+	return norm(treePairs(it), p)
+}
+
+func norm(pairs []Pair, p float64) []float64 {
+	if p < 1 || math.IsNaN(p) {
+		panic("landscape: invalid norm")
+	}
+	var n []float64
+	piecewise(pairs, func(_ int, λₖ []Breakpoint) {
+		var s float64
+		for i := 1; i < len(λₖ); i++ {
+			a, b := λₖ[i-1], λₖ[i]
+			if math.IsInf(p, 1) {
+				s = math.Max(s, b.Lambda)
+			} else {
+				s += segmentPow(a, b, p)
+			}
+		}
+		if !math.IsInf(p, 1) {
+			s = math.Pow(s, 1/p)
+		}
+		n = append(n, s)
+	})
+	return n
+}
+
+// segmentPow returns the integral of λᵖ over the linear segment from a to b.
+func segmentPow(a, b Breakpoint, p float64) float64 {
+	w := b.T - a.T
+	if w == 0 {
+		return 0
+	}
+	if a.Lambda == b.Lambda {
+		return w * math.Pow(a.Lambda, p)
+	}
+	return w * (math.Pow(b.Lambda, p+1) - math.Pow(a.Lambda, p+1)) / ((p + 1) * (b.Lambda - a.Lambda))
+}
+
+// Integral returns the integral of each persistence landscape function λₖ of the interval
+// data in the provided Interface over the window [window.Start, window.End], indexed by k.
+func Integral(data Interface, window interval.IntRange) []float64 {
+	return integral(pairsOf(data), window)
+}
+
+// IntegralTree returns the integral of each persistence landscape function λₖ of the
+// interval data in the provided interval tree over the window, as described for Integral.
+func IntegralTree(it *interval.IntTree, window interval.IntRange) []float64 {
+	return integral(treePairs(it), window)
+}
+
+func integral(pairs []Pair, window interval.IntRange) []float64 {
+	lo, hi := float64(window.Start), float64(window.End)
+	var in []float64
+	piecewise(pairs, func(_ int, λₖ []Breakpoint) {
+		var s float64
+		for i := 1; i < len(λₖ); i++ {
+			a, b := λₖ[i-1], λₖ[i]
+			l, r := math.Max(a.T, lo), math.Min(b.T, hi)
+			if l >= r {
+				continue
+			}
+			s += (r - l) * (lerp(a, b, l) + lerp(a, b, r)) / 2
+		}
+		in = append(in, s)
+	})
+	return in
+}
+
+// lerp returns the value at t of the linear segment from a to b.
+func lerp(a, b Breakpoint, t float64) float64 {
+	return a.Lambda + (b.Lambda-a.Lambda)*(t-a.T)/(b.T-a.T)
+}
+
+// Layers returns the number of persistence landscape functions λₖ of the interval data
+// in the provided Interface that are not zero everywhere.
+func Layers(data Interface) int {
+	return layers(pairsOf(data))
+}
+
+// LayersTree returns the number of persistence landscape functions λₖ of the interval
+// data in the provided interval tree that are not zero everywhere.
+func LayersTree(it *interval.IntTree) int {
+	return layers(treePairs(it))
+}
+
+// layers returns the maximum number of pairs whose open intervals
+// share a common point.
+func layers(pairs []Pair) int {
+	births := make([]float64, 0, len(pairs))
+	deaths := make([]float64, 0, len(pairs))
+	for _, p := range pairs {
+		if p.Death > p.Birth {
+			births = append(births, p.Birth)
+			deaths = append(deaths, p.Death)
+		}
+	}
+	sort.Float64s(births)
+	sort.Float64s(deaths)
+	var n, max, j int
+	for _, b := range births {
+		for deaths[j] <= b {
+			j++
+			n--
+		}
+		n++
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
@@ -0,0 +1,61 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"math"
+	"math/rand"
+
+	"gopkg.in/check.v1"
+
+	"github.com/biogo/store/interval"
+)
+
+func (s *S) TestNorm(c *check.C) {
+	data := ivs{{Start: 0, End: 4}, {Start: 2, End: 6}, {Start: 10, End: 10}}
+	c.Check(Norm(data, 1), check.DeepEquals, []float64{7, 1})
+	c.Check(Norm(data, math.Inf(1)), check.DeepEquals, []float64{2, 1})
+	c.Check(Norm(data, 2)[1], check.Equals, math.Sqrt(2.0/3))
+	c.Check(Norm(ivs(nil), 1), check.IsNil)
+	c.Check(func() { Norm(data, 0.5) }, check.PanicMatches, "landscape: invalid norm")
+
+	c.Check(Integral(data, interval.IntRange{Start: 0, End: 6}), check.DeepEquals, []float64{7, 1})
+	c.Check(Integral(data, interval.IntRange{Start: 3, End: 4}), check.DeepEquals, []float64{1.5, 0.5})
+	c.Check(Integral(data, interval.IntRange{Start: 7, End: 20}), check.DeepEquals, []float64{0, 0})
+
+	c.Check(Layers(data), check.Equals, 2)
+	c.Check(Layers(ivs{{Start: 0, End: 2}, {Start: 2, End: 4}}), check.Equals, 1)
+	c.Check(Layers(ivs(nil)), check.Equals, 0)
+}
+
+func (s *S) TestStatsRandom(c *check.C) {
+	for n := 0; n < 20; n++ {
+		var (
+			data ivs
+			it   interval.IntTree
+		)
+		for i := 0; i < 50; i++ {
+			s := rand.Intn(200)
+			e := iv{Start: s, End: s + rand.Intn(50), UID: uintptr(i)}
+			data = append(data, e)
+			it.Insert(e, false)
+		}
+		λ := piecewiseOf(append(ivs(nil), data...))
+
+		// Breakpoints fall on half-integers, so the trapezoid
+		// rule with half-integer steps is exact for the L1 norm.
+		want := make([]float64, len(λ))
+		for k, l := range λ {
+			for t := -0.5; t < 260; t += 0.5 {
+				want[k] += (at(l, t) + at(l, t+0.5)) / 4
+			}
+		}
+		c.Check(Norm(data, 1), check.DeepEquals, want)
+		c.Check(NormTree(&it, 1), check.DeepEquals, want)
+		c.Check(IntegralTree(&it, interval.IntRange{Start: -10, End: 300}), check.DeepEquals, want)
+		c.Check(Layers(data), check.Equals, len(λ))
+		c.Check(LayersTree(&it), check.Equals, len(λ))
+	}
+}
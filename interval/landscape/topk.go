@@ -0,0 +1,91 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// topHeap is a min-heap holding the k largest values pushed to it.
+type topHeap struct {
+	k int
+	v []int
+}
+
+func (h *topHeap) Len() int             { return len(h.v) }
+func (h *topHeap) Less(i, j int) bool   { return h.v[i] < h.v[j] }
+func (h *topHeap) Swap(i, j int)        { h.v[i], h.v[j] = h.v[j], h.v[i] }
+func (h *topHeap) Push(x interface{})   { h.v = append(h.v, x.(int)) }
+func (h *topHeap) Pop() (i interface{}) { i, h.v = h.v[len(h.v)-1], h.v[:len(h.v)-1]; return i }
+
+// add adds v to the heap if it is among the k largest values seen.
+func (h *topHeap) add(v int) {
+	switch {
+	case len(h.v) < h.k:
+		heap.Push(h, v)
+	case len(h.v) != 0 && v > h.v[0]:
+		h.v[0] = v
+		heap.Fix(h, 0)
+	}
+}
+
+// DescribeTopK calculates the first k persistence landscape functions λₖ for the interval
+// data in the provided Interface. fn is called for each position t of the span of the
+// interval data with the values for t and the first k λ functions at t, and is called
+// with values identical to the first k values passed by Describe. The memory used to
+// hold the values at each position is bounded by k rather than by the depth of the data.
+func DescribeTopK(data Interface, k int, fn func(t int, λₜ []int)) {
+	if data == nil || data.Len() == 0 {
+		return
+	}
+	sort.Sort(data)
+	var (
+		h   endRangeHeap
+		iv  = data.Item(0)
+		t   = iv.Start
+		end = iv.End
+		top = topHeap{k: k, v: make([]int, 0, max(k, 0))}
+	)
+	describe := func(t int) {
+		for len(h) > 0 && h[0].End <= t {
+			heap.Pop(&h)
+			top.add(0)
+		}
+		for _, iv := range h {
+			if iv.Start == t {
+				top.add(0)
+			}
+			if v := max(0, min(t-iv.Start, iv.End-t)); v > 0 {
+				top.add(v)
+			}
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(top.v)))
+		fn(t, top.v)
+		top.v = top.v[:0]
+	}
+	for i := 0; i < data.Len(); i++ {
+		iv = data.Item(i)
+		if iv.End > end {
+			end = iv.End
+		}
+		if s := iv.Start; s >= t || i == data.Len()-1 {
+			if i == data.Len()-1 {
+				heap.Push(&h, iv)
+				s = iv.End
+			}
+			for ; t < s; t++ {
+				describe(t)
+			}
+		}
+		if i != data.Len()-1 {
+			heap.Push(&h, iv)
+		} else {
+			for ; t <= end; t++ {
+				describe(t)
+			}
+		}
+	}
+}
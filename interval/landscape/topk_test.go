@@ -0,0 +1,72 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package landscape
+
+import (
+	"math/rand"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+func describeTopK(data ivs, k int) (r []lr) {
+	DescribeTopK(data, k, func(pos int, l []int) {
+		r = append(r, lr{pos, append([]int(nil), l...)})
+	})
+	return r
+}
+
+func describeFirst(data ivs, k int) (r []lr) {
+	Describe(data, func(pos int, l []int) {
+		r = append(r, lr{pos, append([]int(nil), l[:min(k, len(l))]...)})
+	})
+	return r
+}
+
+func (s *S) TestDescribeTopK(c *check.C) {
+	for i, t := range testData {
+		for k := 0; k < 4; k++ {
+			c.Check(describeTopK(append(ivs(nil), t.ivs...), k), check.DeepEquals,
+				describeFirst(append(ivs(nil), t.ivs...), k), check.Commentf("Test %d: k=%d", i, k))
+		}
+	}
+	for n := 0; n < 10; n++ {
+		var data ivs
+		for i := 0; i < 100; i++ {
+			s := rand.Intn(200)
+			data = append(data, iv{Start: s, End: s + rand.Intn(50)})
+		}
+		for _, k := range []int{1, 3, 10} {
+			c.Check(describeTopK(append(ivs(nil), data...), k), check.DeepEquals,
+				describeFirst(append(ivs(nil), data...), k), check.Commentf("k=%d", k))
+		}
+	}
+}
+
+// deep returns n heavily overlapping intervals.
+func deep(n int) ivs {
+	data := make(ivs, n)
+	for i := range data {
+		s := rand.Intn(1000)
+		data[i] = iv{Start: s, End: s + 500}
+	}
+	return data
+}
+
+func BenchmarkDescribe(b *testing.B) {
+	data := deep(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Describe(data, func(int, []int) {})
+	}
+}
+
+func BenchmarkDescribeTopK(b *testing.B) {
+	data := deep(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DescribeTopK(data, 3, func(int, []int) {})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package nclist implements a nested containment list interval index.
+//
+// The details of the data structure are described in 'Nested Containment List (NCList):
+// a new algorithm for accelerating interval query of genome alignment and interval
+// databases.' A. V. Alekseyenko and C. J. Lee doi:10.1093/bioinformatics/btl647
+package nclist
+
+import (
+	"sort"
+
+	"github.com/biogo/store/interval"
+)
+
+// An Entry is an interval held by a List.
+type Entry struct {
+	Elem     interval.IntInterface
+	Interval interval.IntRange
+
+	// Sub and N are the index into the List's
+	// entries and the number of entries of the
+	// sublist of intervals contained by Elem.
+	Sub, N int
+}
+
+// A List is a nested containment list. Each level of the list holds intervals that are not
+// contained by any other interval in the level, with intervals contained by an interval held
+// in its sublist. A List is static; it is constructed from a slice of intervals and can not
+// be altered.
+type List struct {
+	// Entries holds all the intervals of the List.
+	// The top level of the list is Entries[:Top].
+	Entries []Entry
+	Top     int
+}
+
+// New returns a List constructed from the intervals in ivs. If any interval in ivs has a
+// start greater than its end, ErrInvertedRange is returned. Intervals are contained by
+// other intervals with the same start and end according to their ID() order.
+func New(ivs []interval.IntInterface) (*List, error) {
+	sorted := make([]Entry, len(ivs))
+	for i, e := range ivs {
+		r := e.Range()
+		if r.Start > r.End {
+			return nil, interval.ErrInvertedRange
+		}
+		sorted[i] = Entry{Elem: e, Interval: r}
+	}
+	sort.Sort(byStartEnd(sorted))
+
+	// Find the parent of each interval, using len(ivs)
+	// as the parent of the top level.
+	var (
+		root   = len(ivs)
+		parent = make([]int, len(ivs))
+		stack  []int
+	)
+	for i, e := range sorted {
+		for len(stack) != 0 && sorted[stack[len(stack)-1]].Interval.End < e.Interval.End {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			parent[i] = root
+		} else {
+			parent[i] = stack[len(stack)-1]
+		}
+		stack = append(stack, i)
+	}
+
+	// Group intervals by parent, retaining sort order.
+	start := make([]int, len(ivs)+2)
+	for _, p := range parent {
+		start[p+1]++
+	}
+	for i := 1; i < len(start); i++ {
+		start[i] += start[i-1]
+	}
+	next := append([]int(nil), start[:len(ivs)+1]...)
+	children := make([]int, len(ivs))
+	for i, p := range parent {
+		children[next[p]] = i
+		next[p]++
+	}
+	kids := func(p int) []int { return children[start[p]:start[p+1]] }
+
+	// Lay out each sublist contiguously in breadth first order.
+	src := make([]int, 0, len(ivs))
+	src = append(src, kids(root)...)
+	top := len(src)
+	for i := 0; i < len(src); i++ {
+		k := kids(src[i])
+		sorted[src[i]].Sub, sorted[src[i]].N = len(src), len(k)
+		src = append(src, k...)
+	}
+	l := &List{Entries: make([]Entry, len(ivs)), Top: top}
+	for i, s := range src {
+		l.Entries[i] = sorted[s]
+	}
+	return l, nil
+}
+
+// Len returns the number of intervals stored in the List.
+func (l *List) Len() int { return len(l.Entries) }
+
+// Get returns a slice of IntInterfaces that overlap q in the List according
+// to q.Overlap().
+func (l *List) Get(q interval.IntOverlapper) (o []interval.IntInterface) {
+	l.doMatch(func(e interval.IntInterface) (done bool) { o = append(o, e); return }, q, l.Entries[:l.Top])
+	return
+}
+
+// DoMatching performs fn on all intervals stored in the List that match q according to
+// Overlap. Intervals within each level of the list are visited in sort order, with the
+// intervals contained by an interval visited immediately after it. q.Overlap() is used
+// to guide traversal and is assumed to return true for any range that contains a range
+// that q overlaps. A boolean is returned indicating whether the traversal was interrupted
+// by an IntOperation returning true. If fn alters stored intervals' end points, future
+// List operation behaviors are undefined.
+func (l *List) DoMatching(fn interval.IntOperation, q interval.IntOverlapper) bool {
+	return l.doMatch(fn, q, l.Entries[:l.Top])
+}
+
+func (l *List) doMatch(fn interval.IntOperation, q interval.IntOverlapper, list []Entry) (done bool) {
+	if len(list) == 0 {
+		return false
+	}
+
+	// Intervals in a level are not contained by each other, so
+	// both starts and ends are in ascending order. The first
+	// interval that may overlap q is the first whose end brings
+	// the span of the level up to that interval into overlap.
+	first := list[0].Interval.Start
+	i := sort.Search(len(list), func(i int) bool {
+		return q.Overlap(interval.IntRange{Start: first, End: list[i].Interval.End})
+	})
+	for _, e := range list[i:] {
+		if !q.Overlap(e.Interval) {
+			break
+		}
+		if fn(e.Elem) {
+			return true
+		}
+		if l.doMatch(fn, q, l.Entries[e.Sub:e.Sub+e.N]) {
+			return true
+		}
+	}
+	return false
+}
+
+// byStartEnd sorts entries by ascending start, descending end and ascending ID.
+type byStartEnd []Entry
+
+func (e byStartEnd) Len() int { return len(e) }
+func (e byStartEnd) Less(i, j int) bool {
+	a, b := e[i].Interval, e[j].Interval
+	switch {
+	case a.Start != b.Start:
+		return a.Start < b.Start
+	case a.End != b.End:
+		return a.End > b.End
+	}
+	return e[i].Elem.ID() < e[j].Elem.ID()
+}
+func (e byStartEnd) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
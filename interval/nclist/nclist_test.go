@@ -0,0 +1,248 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nclist
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"gopkg.in/check.v1"
+
+	"github.com/biogo/store/interval"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+type iv struct {
+	Start, End int
+	UID        uintptr
+}
+
+func (i iv) Overlap(b interval.IntRange) bool { return i.End > b.Start && i.Start < b.End }
+func (i iv) ID() uintptr                      { return i.UID }
+func (i iv) Range() interval.IntRange         { return interval.IntRange{Start: i.Start, End: i.End} }
+func (i iv) String() string                   { return fmt.Sprintf("[%d,%d)", i.Start, i.End) }
+
+// Is every interval in a level uncontained by the others in the level, and does
+// every interval contain its sublist?
+func (l *List) isNested() bool {
+	var check func(list []Entry) bool
+	check = func(list []Entry) bool {
+		for i, e := range list {
+			if i != 0 {
+				p := list[i-1].Interval
+				if p.Start > e.Interval.Start || p.End >= e.Interval.End {
+					return false
+				}
+			}
+			for _, s := range l.Entries[e.Sub : e.Sub+e.N] {
+				if s.Interval.Start < e.Interval.Start || s.Interval.End > e.Interval.End {
+					return false
+				}
+			}
+			if !check(l.Entries[e.Sub : e.Sub+e.N]) {
+				return false
+			}
+		}
+		return true
+	}
+	return check(l.Entries[:l.Top])
+}
+
+func ids(ivs []interval.IntInterface) []uintptr {
+	id := make([]uintptr, len(ivs))
+	for i, e := range ivs {
+		id[i] = e.ID()
+	}
+	sort.Slice(id, func(i, j int) bool { return id[i] < id[j] })
+	return id
+}
+
+func (s *S) TestNew(c *check.C) {
+	l, err := New(nil)
+	c.Assert(err, check.Equals, nil)
+	c.Check(l.Len(), check.Equals, 0)
+	c.Check(l.Get(iv{Start: 0, End: 10}), check.DeepEquals, []interval.IntInterface(nil))
+
+	_, err = New([]interval.IntInterface{iv{Start: 1, End: 0}})
+	c.Check(err, check.Equals, interval.ErrInvertedRange)
+
+	l, err = New([]interval.IntInterface{
+		iv{Start: 0, End: 10, UID: 0},
+		iv{Start: 2, End: 4, UID: 1},
+		iv{Start: 3, End: 4, UID: 2},
+		iv{Start: 5, End: 12, UID: 3},
+		iv{Start: 0, End: 10, UID: 4},
+		iv{Start: 20, End: 30, UID: 5},
+	})
+	c.Assert(err, check.Equals, nil)
+	c.Check(l.isNested(), check.Equals, true)
+	c.Check(l.Top, check.Equals, 3)
+	c.Check(l.Len(), check.Equals, 6)
+	c.Check(ids(l.Get(iv{Start: 3, End: 6})), check.DeepEquals, []uintptr{0, 1, 2, 3, 4})
+	c.Check(ids(l.Get(iv{Start: 10, End: 21})), check.DeepEquals, []uintptr{3, 5})
+	c.Check(ids(l.Get(iv{Start: 12, End: 20})), check.DeepEquals, []uintptr{})
+}
+
+func (s *S) TestRandom(c *check.C) {
+	for _, maxLen := range []int{10, 100, 1000} {
+		var (
+			ivs []interval.IntInterface
+			t   interval.IntTree
+		)
+		for i := 0; i < 1000; i++ {
+			s := rand.Intn(1000)
+			e := iv{Start: s, End: s + rand.Intn(maxLen), UID: uintptr(i)}
+			ivs = append(ivs, e)
+			t.Insert(e, false)
+		}
+		l, err := New(ivs)
+		c.Assert(err, check.Equals, nil)
+		c.Check(l.isNested(), check.Equals, true)
+		c.Check(l.Len(), check.Equals, len(ivs))
+		for i := 0; i < 100; i++ {
+			s := rand.Intn(1100) - 50
+			q := iv{Start: s, End: s + rand.Intn(100)}
+			c.Check(ids(l.Get(q)), check.DeepEquals, ids(t.Get(q)))
+		}
+	}
+}
+
+func (s *S) TestDoMatchingDone(c *check.C) {
+	l, err := New([]interval.IntInterface{
+		iv{Start: 0, End: 10, UID: 0},
+		iv{Start: 2, End: 4, UID: 1},
+		iv{Start: 5, End: 12, UID: 2},
+	})
+	c.Assert(err, check.Equals, nil)
+	var n int
+	c.Check(l.DoMatching(func(e interval.IntInterface) (done bool) {
+		n++
+		return e.ID() == 1
+	}, iv{Start: 3, End: 6}), check.Equals, true)
+	c.Check(n, check.Equals, 2)
+}
+
+// Benchmarks
+
+type compInt int
+
+func (c compInt) Compare(b interval.Comparable) int { return int(c - b.(compInt)) }
+
+type treeIv struct {
+	start, end compInt
+	id         uintptr
+}
+
+func (i *treeIv) Overlap(b interval.Range) bool {
+	return i.end > b.Start().(compInt) && i.start < b.End().(compInt)
+}
+func (i *treeIv) ID() uintptr                    { return i.id }
+func (i *treeIv) Start() interval.Comparable     { return i.start }
+func (i *treeIv) End() interval.Comparable       { return i.end }
+func (i *treeIv) SetStart(c interval.Comparable) { i.start = c.(compInt) }
+func (i *treeIv) SetEnd(c interval.Comparable)   { i.end = c.(compInt) }
+func (i *treeIv) NewMutable() interval.Mutable   { return &treeIv{i.start, i.end, i.id} }
+
+// flat returns n overlapping intervals with no nesting.
+func flat(n int) []iv {
+	ivs := make([]iv, n)
+	for i := range ivs {
+		ivs[i] = iv{Start: i * 10, End: i*10 + 15, UID: uintptr(i)}
+	}
+	return ivs
+}
+
+// nested returns n intervals in loci holding genes holding transcripts.
+func nested(n int) []iv {
+	ivs := make([]iv, 0, n)
+	for locus := 0; len(ivs) < n; locus += 1000 {
+		ivs = append(ivs, iv{Start: locus, End: locus + 1000})
+		for gene := locus; gene < locus+1000 && len(ivs) < n; gene += 200 {
+			ivs = append(ivs, iv{Start: gene, End: gene + 150})
+			for tx := 0; tx < 4 && len(ivs) < n; tx++ {
+				ivs = append(ivs, iv{Start: gene + tx*10, End: gene + 150 - tx*10})
+			}
+		}
+	}
+	for i := range ivs {
+		ivs[i].UID = uintptr(i)
+	}
+	return ivs
+}
+
+var benchQueries = func() []iv {
+	q := make([]iv, 1000)
+	for i := range q {
+		s := rand.Intn(1e6)
+		q[i] = iv{Start: s, End: s + 50}
+	}
+	return q
+}()
+
+func benchmarkList(b *testing.B, data []iv) {
+	ivs := make([]interval.IntInterface, len(data))
+	for i, e := range data {
+		ivs[i] = e
+	}
+	l, err := New(ivs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Get(benchQueries[i%len(benchQueries)])
+	}
+}
+
+func benchmarkIntTree(b *testing.B, data []iv) {
+	var t interval.IntTree
+	for _, e := range data {
+		t.Insert(e, true)
+	}
+	t.AdjustRanges()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.Get(benchQueries[i%len(benchQueries)])
+	}
+}
+
+func benchmarkTree(b *testing.B, data []iv) {
+	var t interval.Tree
+	for _, e := range data {
+		t.Insert(&treeIv{start: compInt(e.Start), end: compInt(e.End), id: e.UID}, true)
+	}
+	t.AdjustRanges()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := benchQueries[i%len(benchQueries)]
+		t.Get(&treeIv{start: compInt(q.Start), end: compInt(q.End)})
+	}
+}
+
+func BenchmarkListFlat(b *testing.B)      { benchmarkList(b, flat(1e5)) }
+func BenchmarkIntTreeFlat(b *testing.B)   { benchmarkIntTree(b, flat(1e5)) }
+func BenchmarkTreeFlat(b *testing.B)      { benchmarkTree(b, flat(1e5)) }
+func BenchmarkListNested(b *testing.B)    { benchmarkList(b, nested(1e5)) }
+func BenchmarkIntTreeNested(b *testing.B) { benchmarkIntTree(b, nested(1e5)) }
+func BenchmarkTreeNested(b *testing.B)    { benchmarkTree(b, nested(1e5)) }
+
+func BenchmarkNew(b *testing.B) {
+	data := nested(1e5)
+	ivs := make([]interval.IntInterface, len(data))
+	for i, e := range data {
+		ivs[i] = e
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New(ivs)
+	}
+}
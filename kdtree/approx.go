@@ -0,0 +1,107 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"container/heap"
+	"math"
+)
+
+// An Approximation specifies the accuracy of an approximate nearest neighbour search.
+type Approximation struct {
+	// Epsilon is the relative error allowed in the search. A subtree
+	// is not searched if its distance from the query multiplied by
+	// 1+Epsilon is greater than the distance to the furthest retained
+	// value. Distances are in the units of the search Metric, so for
+	// the squared Euclidean metric the returned neighbours are within
+	// a factor of sqrt(1+Epsilon) of the true Euclidean distances.
+	Epsilon float64
+
+	// MaxVisits is the maximum number of nodes to visit
+	// during the search. If MaxVisits is zero, the number
+	// of visits is not limited.
+	MaxVisits int
+}
+
+// NearestApprox returns an approximation of the nearest value to the query according to
+// the metric m, and the distance between them. The search visits subtrees in order of their
+// distance from the query, stopping when the constraints in a are reached. The returned
+// exact flag is true if the result is guaranteed to be the exact nearest value.
+func (t *Tree) NearestApprox(q Comparable, m Metric, a Approximation) (c Comparable, dist float64, exact bool) {
+	k := NewNKeeper(1)
+	exact = t.NearestSetApprox(k, q, m, a)
+	if k.Len() == 0 {
+		return nil, inf, exact
+	}
+	return k.Heap[0].Comparable, k.Heap[0].Dist, exact
+}
+
+// NearestSetApprox finds an approximation of the nearest values to the query accepted
+// by the provided Keeper, k, according to the metric m. The search visits subtrees in
+// order of their distance from the query, stopping when the constraints in a are reached.
+// The behaviour of k is as described for NearestSet. The returned flag is true if the
+// retained values are guaranteed to be those that would be retained by NearestSetMetric.
+//
+// The search strategy is described in 'Shape indexing using approximate nearest-neighbour
+// search in high-dimensional spaces.' J. S. Beis and D. G. Lowe
+// doi:10.1109/CVPR.1997.609451
+func (t *Tree) NearestSetApprox(k Keeper, q Comparable, m Metric, a Approximation) (exact bool) {
+	if t.Root == nil {
+		return true
+	}
+	exact = t.Root.searchApprox(q, m, k, a)
+	finish(k)
+	return exact
+}
+
+func (n *Node) searchApprox(q Comparable, m Metric, k Keeper, a Approximation) (exact bool) {
+	var (
+		bins   = binHeap{{node: n}}
+		visits int
+	)
+	for len(bins) != 0 {
+		b := heap.Pop(&bins).(bin)
+		max := k.Max().Dist
+		if b.bound > max {
+			return true
+		}
+		if b.bound*(1+a.Epsilon) > max {
+			return false
+		}
+		for n := b.node; n != nil; {
+			if a.MaxVisits != 0 && visits == a.MaxVisits {
+				return false
+			}
+			visits++
+
+			c := q.Compare(n.Point, n.Plane)
+			k.Keep(ComparableDist{Comparable: n.Point, Dist: m.Distance(q, n.Point)})
+			near, far := n.Left, n.Right
+			if c > 0 {
+				near, far = far, near
+			}
+			if far != nil {
+				heap.Push(&bins, bin{node: far, bound: math.Max(b.bound, m.PlaneDistance(c, n.Plane))})
+			}
+			n = near
+		}
+	}
+	return true
+}
+
+// bin is an unsearched subtree and a lower bound on its distance from a query.
+type bin struct {
+	node  *Node
+	bound float64
+}
+
+// binHeap is a min heap of bins sorted on bound.
+type binHeap []bin
+
+func (h binHeap) Len() int              { return len(h) }
+func (h binHeap) Less(i, j int) bool    { return h[i].bound < h[j].bound }
+func (h binHeap) Swap(i, j int)         { h[i], h[j] = h[j], h[i] }
+func (h *binHeap) Push(x interface{})   { *h = append(*h, x.(bin)) }
+func (h *binHeap) Pop() (i interface{}) { i, *h = (*h)[len(*h)-1], (*h)[:len(*h)-1]; return i }
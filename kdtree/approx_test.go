@@ -0,0 +1,93 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+func randPoints(n, dims int) Points {
+	p := make(Points, n)
+	for i := range p {
+		p[i] = make(Point, dims)
+		for j := range p[i] {
+			p[i][j] = rand.Float64()
+		}
+	}
+	return p
+}
+
+func (s *S) TestNearestApprox(c *check.C) {
+	const dims = 20
+	data := randPoints(2000, dims)
+	t := New(append(Points(nil), data...), false)
+	var inexact int
+	for i := 0; i < 100; i++ {
+		q := randPoints(1, dims)[0]
+		_, want := nearest(q, data)
+
+		for _, m := range []Metric{Euclidean{}, Manhattan{}} {
+			exactP, exactD := t.NearestMetric(q, m)
+			p, d, exact := t.NearestApprox(q, m, Approximation{})
+			c.Check(exact, check.Equals, true)
+			c.Check(p, check.DeepEquals, exactP)
+			c.Check(d, check.Equals, exactD)
+
+			want := NewNKeeper(10)
+			t.NearestSetMetric(want, q, m)
+			got := NewNKeeper(10)
+			c.Check(t.NearestSetApprox(got, q, m, Approximation{}), check.Equals, true)
+			c.Check(got.Heap, check.DeepEquals, want.Heap)
+		}
+
+		const eps = 0.5
+		_, d, exact := t.NearestApprox(q, Euclidean{}, Approximation{Epsilon: eps})
+		c.Check(d <= want*(1+eps), check.Equals, true)
+		if exact {
+			c.Check(d, check.Equals, want)
+		}
+
+		_, d, exact = t.NearestApprox(q, Euclidean{}, Approximation{MaxVisits: 50})
+		c.Check(d >= want, check.Equals, true)
+		if exact {
+			c.Check(d, check.Equals, want)
+		} else {
+			inexact++
+		}
+
+		got := NewNKeeper(10)
+		t.NearestSetApprox(got, q, Euclidean{}, Approximation{MaxVisits: 1})
+		c.Check(got.Len(), check.Equals, 1)
+	}
+	// Visiting 50 of 2000 nodes in 20 dimensions
+	// is not enough to prove every result exact.
+	c.Check(inexact > 0, check.Equals, true)
+
+	_, d, exact := (&Tree{}).NearestApprox(Point{0}, Euclidean{}, Approximation{})
+	c.Check(d, check.Equals, math.Inf(1))
+	c.Check(exact, check.Equals, true)
+}
+
+func benchmarkNearestApprox(b *testing.B, a Approximation) {
+	data := randPoints(1e4, 50)
+	queries := randPoints(100, 50)
+	t := New(data, false)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.NearestApprox(queries[i%len(queries)], Euclidean{}, a)
+	}
+}
+
+func BenchmarkNearestApproxExact(b *testing.B) { benchmarkNearestApprox(b, Approximation{}) }
+func BenchmarkNearestApproxEps(b *testing.B) {
+	benchmarkNearestApprox(b, Approximation{Epsilon: 1})
+}
+func BenchmarkNearestApproxVisits(b *testing.B) {
+	benchmarkNearestApprox(b, Approximation{MaxVisits: 200})
+}
@@ -0,0 +1,131 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// KNNBatch returns the k nearest neighbours of each of the queries, in ascending order
+// of distance, using up to workers goroutines. The returned slices are indexed by query
+// and hold fewer than k values if the tree holds fewer than k points. The tree must not
+// be altered during the call.
+func (t *Tree) KNNBatch(queries []Comparable, k, workers int) [][]ComparableDist {
+	return t.knnBatch(queries, k, workers, false)
+}
+
+// KNNBatchExcludeSelf returns the k nearest neighbours of each of the queries as described
+// for KNNBatch, except that for each query one point with the same coordinates as the query
+// is excluded from its neighbours. This allows the neighbourhoods of the points stored in
+// the tree to be found by querying with the stored points.
+func (t *Tree) KNNBatchExcludeSelf(queries []Comparable, k, workers int) [][]ComparableDist {
+	return t.knnBatch(queries, k, workers, true)
+}
+
+// batchChunk is the number of queries claimed by a KNNBatch worker at a time.
+const batchChunk = 64
+
+func (t *Tree) knnBatch(queries []Comparable, k, workers int, excludeSelf bool) [][]ComparableDist {
+	if k < 1 || t.Root == nil {
+		return make([][]ComparableDist, len(queries))
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	n := k
+	if excludeSelf {
+		n++
+	}
+
+	// All results share a single backing slice.
+	var (
+		results = make([][]ComparableDist, len(queries))
+		buf     = make([]ComparableDist, len(queries)*k)
+		next    int64
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			keep := NewNKeeper(n)
+			for {
+				start := int(atomic.AddInt64(&next, batchChunk)) - batchChunk
+				if start >= len(queries) {
+					return
+				}
+				end := start + batchChunk
+				if end > len(queries) {
+					end = len(queries)
+				}
+				for i, q := range queries[start:end] {
+					keep.Heap = keep.Heap[:1]
+					keep.Heap[0] = ComparableDist{Dist: inf}
+					t.NearestSet(keep, q)
+
+					nn := keep.Heap
+					if excludeSelf {
+						for j, c := range nn {
+							if c.Comparable != nil && sameCoordinates(q, c.Comparable) {
+								copy(nn[j:], nn[j+1:])
+								nn = nn[:len(nn)-1]
+								break
+							}
+						}
+					}
+					if len(nn) > k {
+						nn = nn[:k]
+					}
+					r := buf[(start+i)*k : (start+i)*k+len(nn) : (start+i+1)*k]
+					copy(r, nn)
+					results[start+i] = r
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// A Graph is a sparse directed graph held in compressed sparse row form. The neighbours
+// of node i are Indices[Offsets[i]:Offsets[i+1]] at the corresponding distances in Dists.
+type Graph struct {
+	Offsets []int
+	Indices []int
+	Dists   []float64
+}
+
+// KNNGraph returns a Graph constructed from the neighbours returned by KNNBatch or
+// KNNBatchExcludeSelf. The ith node of the graph corresponds to neighbours[i], and index
+// returns the node index of each neighbour.
+func KNNGraph(neighbours [][]ComparableDist, index func(Comparable) int) *Graph {
+	var n int
+	for _, nn := range neighbours {
+		n += len(nn)
+	}
+	g := &Graph{
+		Offsets: make([]int, 1, len(neighbours)+1),
+		Indices: make([]int, 0, n),
+		Dists:   make([]float64, 0, n),
+	}
+	for _, nn := range neighbours {
+		for _, c := range nn {
+			g.Indices = append(g.Indices, index(c.Comparable))
+			g.Dists = append(g.Dists, c.Dist)
+		}
+		g.Offsets = append(g.Offsets, len(g.Indices))
+	}
+	return g
+}
+
+// Len returns the number of nodes in the graph.
+func (g *Graph) Len() int { return len(g.Offsets) - 1 }
+
+// Neighbours returns the indices of the neighbours of node i and the distances to them.
+func (g *Graph) Neighbours(i int) (indices []int, dists []float64) {
+	s, e := g.Offsets[i], g.Offsets[i+1]
+	return g.Indices[s:e], g.Dists[s:e]
+}
@@ -0,0 +1,132 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"math/rand"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+// indexedPoint is a Point labelled with its index in a data set.
+type indexedPoint struct {
+	Point
+	index int
+}
+
+func (p indexedPoint) Compare(c Comparable, d Dim) float64 {
+	return p.Point.Compare(c.(indexedPoint).Point, d)
+}
+func (p indexedPoint) Distance(c Comparable) float64 { return p.Point.Distance(c.(indexedPoint).Point) }
+
+type indexedPoints []indexedPoint
+
+func (p indexedPoints) Index(i int) Comparable         { return p[i] }
+func (p indexedPoints) Len() int                       { return len(p) }
+func (p indexedPoints) Slice(start, end int) Interface { return p[start:end] }
+func (p indexedPoints) Pivot(d Dim) int                { return indexedPlane{indexedPoints: p, Dim: d}.Pivot() }
+
+type indexedPlane struct {
+	Dim
+	indexedPoints
+}
+
+func (p indexedPlane) Less(i, j int) bool {
+	return p.indexedPoints[i].Point[p.Dim] < p.indexedPoints[j].Point[p.Dim]
+}
+func (p indexedPlane) Pivot() int { return Partition(p, MedianOfRandoms(p, Randoms)) }
+func (p indexedPlane) Slice(start, end int) SortSlicer {
+	p.indexedPoints = p.indexedPoints[start:end]
+	return p
+}
+func (p indexedPlane) Swap(i, j int) {
+	p.indexedPoints[i], p.indexedPoints[j] = p.indexedPoints[j], p.indexedPoints[i]
+}
+
+func randIndexed(n int) (indexedPoints, []Comparable) {
+	data := make(indexedPoints, n)
+	queries := make([]Comparable, n)
+	for i := range data {
+		data[i] = indexedPoint{Point: Point{rand.Float64(), rand.Float64(), rand.Float64()}, index: i}
+		queries[i] = data[i]
+	}
+	return data, queries
+}
+
+func (s *S) TestKNNBatch(c *check.C) {
+	const k = 5
+	data, queries := randIndexed(1000)
+	t := New(append(indexedPoints(nil), data...), false)
+	for _, workers := range []int{0, 1, 4} {
+		got := t.KNNBatch(queries, k, workers)
+		excl := t.KNNBatchExcludeSelf(queries, k, workers)
+		c.Assert(len(got), check.Equals, len(queries))
+		c.Assert(len(excl), check.Equals, len(queries))
+		for i, q := range queries {
+			want := NewNKeeper(k + 1)
+			t.NearestSet(want, q)
+			c.Check(got[i], check.DeepEquals, []ComparableDist(want.Heap[:k]))
+			c.Check(got[i][0].Comparable.(indexedPoint).index, check.Equals, i)
+			c.Check(excl[i], check.DeepEquals, []ComparableDist(want.Heap[1:]))
+		}
+	}
+
+	small := New(append(indexedPoints(nil), data[:3]...), false)
+	for _, nn := range small.KNNBatch(queries[:10], k, 2) {
+		c.Check(len(nn), check.Equals, 3)
+	}
+	for _, nn := range small.KNNBatchExcludeSelf(queries[:3], k, 2) {
+		c.Check(len(nn), check.Equals, 2)
+	}
+	c.Check(t.KNNBatch(queries[:2], 0, 2), check.DeepEquals, [][]ComparableDist{nil, nil})
+
+	empty := &Tree{}
+	for _, nn := range empty.KNNBatch(queries[:10], k, 2) {
+		c.Check(nn, check.HasLen, 0)
+	}
+	for _, nn := range empty.KNNBatchExcludeSelf(queries[:10], k, 2) {
+		c.Check(nn, check.HasLen, 0)
+	}
+}
+
+func (s *S) TestKNNGraph(c *check.C) {
+	const k = 3
+	data, queries := randIndexed(100)
+	t := New(append(indexedPoints(nil), data...), false)
+	nn := t.KNNBatchExcludeSelf(queries, k, 2)
+	g := KNNGraph(nn, func(c Comparable) int { return c.(indexedPoint).index })
+	c.Check(g.Len(), check.Equals, len(queries))
+	c.Check(len(g.Indices), check.Equals, len(queries)*k)
+	for i := range queries {
+		idx, dists := g.Neighbours(i)
+		c.Assert(len(idx), check.Equals, k)
+		for j, e := range nn[i] {
+			c.Check(idx[j], check.Equals, e.Comparable.(indexedPoint).index)
+			c.Check(idx[j], check.Not(check.Equals), i)
+			c.Check(dists[j], check.Equals, data[i].Distance(data[idx[j]]))
+		}
+	}
+}
+
+func BenchmarkKNNBatch(b *testing.B) {
+	data, queries := randIndexed(1e4)
+	t := New(data, false)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.KNNBatch(queries, 15, 4)
+	}
+}
+
+func BenchmarkKNNSerial(b *testing.B) {
+	data, queries := randIndexed(1e4)
+	t := New(data, false)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, q := range queries {
+			t.NearestSet(NewNKeeper(15), q)
+		}
+	}
+}
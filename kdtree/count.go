@@ -0,0 +1,134 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import "math"
+
+// CountWithin returns the number of values in the tree within distance r of the query,
+// where distance is measured by the Distance method of the query. The result is the same
+// as the number of values retained by a NearestSet search with a DistKeeper holding r,
+// but values are not retained. If the tree has bounding volumes, subtrees with volumes
+// entirely within r of the query are counted without visiting their nodes.
+func (t *Tree) CountWithin(q Comparable, r float64) int {
+	if t.Root == nil {
+		return 0
+	}
+	return t.Root.countWithin(q, r, t.Root.Bounding != nil)
+}
+
+// countWithin counts the values within r of q in the subtree rooted at n, using the
+// subtree bounding volumes if bounded is true.
+func (n *Node) countWithin(q Comparable, r float64, bounded bool) int {
+	if n == nil {
+		return 0
+	}
+	if bounded && n.Bounding != nil {
+		near, far := n.Bounding.distances(q)
+		if near > r {
+			return 0
+		}
+		if far <= r {
+			return n.size()
+		}
+	}
+
+	var count int
+	if q.Distance(n.Point) <= r {
+		count++
+	}
+	c := q.Compare(n.Point, n.Plane)
+	if c <= 0 {
+		count += n.Left.countWithin(q, r, bounded)
+		if c*c <= r {
+			count += n.Right.countWithin(q, r, bounded)
+		}
+		return count
+	}
+	count += n.Right.countWithin(q, r, bounded)
+	if c*c <= r {
+		count += n.Left.countWithin(q, r, bounded)
+	}
+	return count
+}
+
+// size returns the number of nodes in the subtree rooted at n, counting
+// the nodes if the Size of n is not known.
+func (n *Node) size() int {
+	if n == nil {
+		return 0
+	}
+	if n.Size != 0 {
+		return n.Size
+	}
+	return 1 + n.Left.size() + n.Right.size()
+}
+
+// distances returns the squared Euclidean distances from q to the nearest and furthest
+// points of the volume.
+func (b *Bounding) distances(q Comparable) (near, far float64) {
+	for d := Dim(0); d < Dim(q.Dims()); d++ {
+		lo, hi := q.Compare(b[0], d), q.Compare(b[1], d)
+		switch {
+		case lo < 0:
+			near += lo * lo
+		case hi > 0:
+			near += hi * hi
+		}
+		v := math.Max(math.Abs(lo), math.Abs(hi))
+		far += v * v
+	}
+	return near, far
+}
+
+// CountBounded returns the number of values in the tree within the specified bound.
+// If b is nil, the result is the same as Len. If the tree has bounding volumes, subtrees
+// with volumes entirely within b are counted without visiting their nodes.
+func (t *Tree) CountBounded(b *Bounding) int {
+	if b == nil {
+		return t.Len()
+	}
+	if t.Root == nil {
+		return 0
+	}
+	return t.Root.countBounded(b, t.Root.Bounding != nil)
+}
+
+// countBounded counts the values within b in the subtree rooted at n, using the
+// subtree bounding volumes if bounded is true.
+func (n *Node) countBounded(b *Bounding, bounded bool) int {
+	if n == nil {
+		return 0
+	}
+	if bounded && n.Bounding != nil {
+		if !n.Bounding.intersects(b) {
+			return 0
+		}
+		if b.Contains(n.Bounding[0]) && b.Contains(n.Bounding[1]) {
+			return n.size()
+		}
+	}
+
+	var count int
+	if b.Contains(n.Point) {
+		count++
+	}
+	if b[0].Compare(n.Point, n.Plane) <= 0 {
+		count += n.Left.countBounded(b, bounded)
+	}
+	if b[1].Compare(n.Point, n.Plane) >= 0 {
+		count += n.Right.countBounded(b, bounded)
+	}
+	return count
+}
+
+// intersects returns whether the volumes b and o intersect.
+func (b *Bounding) intersects(o *Bounding) bool {
+	for d := Dim(0); d < Dim(b[0].Dims()); d++ {
+		if b[1].Compare(o[0], d) < 0 || b[0].Compare(o[1], d) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"math/rand"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestCount(c *check.C) {
+	data := make(Points, 1000)
+	for i := range data {
+		// Use a small range to ensure coincident coordinates.
+		data[i] = Point{float64(rand.Intn(20)), float64(rand.Intn(20)), float64(rand.Intn(20))}
+	}
+	for _, bounding := range []bool{false, true} {
+		t := New(append(Points(nil), data...), bounding)
+		c.Check(t.Root.isSplit(), check.Equals, true)
+		for i := 0; i < 100; i++ {
+			q := Point{float64(rand.Intn(20)), float64(rand.Intn(20)), float64(rand.Intn(20))}
+			r := float64(rand.Intn(50))
+			var want int
+			for _, p := range data {
+				if q.Distance(p) <= r {
+					want++
+				}
+			}
+			c.Check(t.CountWithin(q, r), check.Equals, want)
+			dk := NewDistKeeper(r)
+			t.NearestSet(dk, q)
+			c.Check(dk.Len(), check.Equals, want)
+
+			lo := Point{float64(rand.Intn(20)), float64(rand.Intn(20)), float64(rand.Intn(20))}
+			hi := Point{lo[0] + float64(rand.Intn(10)), lo[1] + float64(rand.Intn(10)), lo[2] + float64(rand.Intn(10))}
+			b := &Bounding{lo, hi}
+			want = 0
+			for _, p := range data {
+				if b.Contains(p) {
+					want++
+				}
+			}
+			c.Check(t.CountBounded(b), check.Equals, want)
+		}
+		c.Check(t.CountBounded(nil), check.Equals, len(data))
+		c.Check(t.CountBounded(&Bounding{Point{-10, -10, -10}, Point{30, 30, 30}}), check.Equals, len(data))
+		c.Check(t.CountWithin(Point{10, 10, 10}, 1e4), check.Equals, len(data))
+	}
+
+	t := New(append(Points(nil), data...), true)
+	for _, p := range data[:100] {
+		t.Insert(p, true)
+		t.Delete(data[len(data)-1-rand.Intn(500)])
+	}
+	c.Check(t.Root.Size, check.Equals, t.Len())
+	c.Check(t.Root.isSplit(), check.Equals, true)
+	c.Check(t.CountBounded(&Bounding{Point{-10, -10, -10}, Point{30, 30, 30}}), check.Equals, t.Len())
+	c.Check((&Tree{}).CountWithin(Point{0, 0, 0}, 1), check.Equals, 0)
+	c.Check((&Tree{}).CountBounded(&Bounding{Point{0, 0, 0}, Point{1, 1, 1}}), check.Equals, 0)
+}
+
+func BenchmarkCountWithin(b *testing.B) {
+	t := New(randPoints(1e5, 3), true)
+	q := Point{0.5, 0.5, 0.5}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.CountWithin(q, 0.1)
+	}
+}
+
+func BenchmarkDistKeeper(b *testing.B) {
+	t := New(randPoints(1e5, 3), true)
+	q := Point{0.5, 0.5, 0.5}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.NearestSet(NewDistKeeper(0.1), q)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+// FlatTree is a k-d tree over points held in a dense row-major slice. The tree has an
+// implicit layout: the root of the subtree held in the rows [lo, hi) is the row at
+// lo+(hi-lo)/2, with the left and right subtrees held in the rows before and after it,
+// and nodes at depth i split on dimension i modulo Dims. No nodes are allocated and
+// points are not boxed in interface values.
+type FlatTree struct {
+	// Data holds the coordinates of the
+	// points in row-major tree order.
+	Data []float64
+
+	// Index holds the index of each point in
+	// the data used to construct the tree.
+	Index []int
+
+	// Dims is the number of dimensions
+	// of the points in the tree.
+	Dims int
+}
+
+// NewFlat returns a FlatTree constructed from the row-major points in data, each with dims
+// dimensions. The rows of data are reordered by NewFlat and data is retained by the
+// returned tree. NewFlat panics if dims is less than one or the length of data is not a
+// multiple of dims.
+func NewFlat(data []float64, dims int) *FlatTree {
+	if dims < 1 || len(data)%dims != 0 {
+		panic("kdtree: invalid flat data shape")
+	}
+	t := &FlatTree{
+		Data:  data,
+		Index: make([]int, len(data)/dims),
+		Dims:  dims,
+	}
+	for i := range t.Index {
+		t.Index[i] = i
+	}
+	t.build(0, len(t.Index), 0)
+	return t
+}
+
+func (t *FlatTree) build(lo, hi int, d Dim) {
+	if hi-lo < 2 {
+		return
+	}
+	mid := lo + (hi-lo)/2
+	SelectRand(flatPlane{
+		data:  t.Data[lo*t.Dims : hi*t.Dims],
+		index: t.Index[lo:hi],
+		dims:  t.Dims,
+		dim:   d,
+	}, mid-lo, nil)
+	d = (d + 1) % Dim(t.Dims)
+	t.build(lo, mid, d)
+	t.build(mid+1, hi, d)
+}
+
+// flatPlane is a SortSlicer over the rows of a FlatTree ordered on a single dimension.
+type flatPlane struct {
+	data  []float64
+	index []int
+	dims  int
+	dim   Dim
+}
+
+func (p flatPlane) Len() int { return len(p.index) }
+func (p flatPlane) Less(i, j int) bool {
+	return p.data[i*p.dims+int(p.dim)] < p.data[j*p.dims+int(p.dim)]
+}
+func (p flatPlane) Swap(i, j int) {
+	p.index[i], p.index[j] = p.index[j], p.index[i]
+	a, b := p.data[i*p.dims:(i+1)*p.dims], p.data[j*p.dims:(j+1)*p.dims]
+	for k := range a {
+		a[k], b[k] = b[k], a[k]
+	}
+}
+func (p flatPlane) Slice(start, end int) SortSlicer {
+	p.data = p.data[start*p.dims : end*p.dims]
+	p.index = p.index[start:end]
+	return p
+}
+
+// Len returns the number of points in the tree.
+func (t *FlatTree) Len() int { return len(t.Index) }
+
+// point returns the coordinates of the ith row of the tree.
+func (t *FlatTree) point(i int) Point {
+	return Point(t.Data[i*t.Dims : (i+1)*t.Dims : (i+1)*t.Dims])
+}
+
+// Nearest returns the index of the nearest point to the query in the data used to construct
+// the tree, and the squared Euclidean distance between them. If the tree is empty, Nearest
+// returns -1 and infinite distance.
+func (t *FlatTree) Nearest(q Point) (int, float64) {
+	best, dist := -1, inf
+	t.search(q, 0, len(t.Index), 0, &best, &dist)
+	if best < 0 {
+		return -1, inf
+	}
+	return t.Index[best], dist
+}
+
+func (t *FlatTree) search(q Point, lo, hi int, d Dim, best *int, dist *float64) {
+	if lo >= hi {
+		return
+	}
+	mid := lo + (hi-lo)/2
+	p := t.point(mid)
+	if pd := q.Distance(p); pd < *dist {
+		*best, *dist = mid, pd
+	}
+	c := q[d] - p[d]
+	next := (d + 1) % Dim(t.Dims)
+	if c <= 0 {
+		t.search(q, lo, mid, next, best, dist)
+		if c*c < *dist {
+			t.search(q, mid+1, hi, next, best, dist)
+		}
+		return
+	}
+	t.search(q, mid+1, hi, next, best, dist)
+	if c*c < *dist {
+		t.search(q, lo, mid, next, best, dist)
+	}
+}
+
+// A FlatPoint is a point held by a FlatTree.
+type FlatPoint struct {
+	// Index is the index of the point in the
+	// data used to construct the tree.
+	Index int
+
+	// Point holds the coordinates of the point.
+	// It refers to the tree's Data.
+	Point Point
+}
+
+// flatCoords returns the coordinates of c, which must be a Point or a FlatPoint.
+func flatCoords(c Comparable) Point {
+	if p, ok := c.(FlatPoint); ok {
+		return p.Point
+	}
+	return c.(Point)
+}
+
+func (p FlatPoint) Compare(c Comparable, d Dim) float64 { return p.Point[d] - flatCoords(c)[d] }
+func (p FlatPoint) Dims() int                           { return len(p.Point) }
+func (p FlatPoint) Distance(c Comparable) float64       { return p.Point.Distance(flatCoords(c)) }
+
+// NearestSet finds the nearest values to the query accepted by the provided Keeper, k, as
+// described for Tree.NearestSet. The values retained by k are FlatPoints holding the index
+// of each point in the data used to construct the tree.
+func (t *FlatTree) NearestSet(k Keeper, q Point) {
+	if len(t.Index) == 0 {
+		return
+	}
+	t.searchSet(q, 0, len(t.Index), 0, k)
+	finish(k)
+}
+
+func (t *FlatTree) searchSet(q Point, lo, hi int, d Dim, k Keeper) {
+	if lo >= hi {
+		return
+	}
+	mid := lo + (hi-lo)/2
+	p := t.point(mid)
+	// Only box values that may be retained.
+	if pd := q.Distance(p); pd <= k.Max().Dist {
+		k.Keep(ComparableDist{Comparable: FlatPoint{Index: t.Index[mid], Point: p}, Dist: pd})
+	}
+	c := q[d] - p[d]
+	next := (d + 1) % Dim(t.Dims)
+	if c <= 0 {
+		t.searchSet(q, lo, mid, next, k)
+		if c*c <= k.Max().Dist {
+			t.searchSet(q, mid+1, hi, next, k)
+		}
+		return
+	}
+	t.searchSet(q, mid+1, hi, next, k)
+	if c*c <= k.Max().Dist {
+		t.searchSet(q, lo, mid, next, k)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+func flatData(n, dims int) []float64 {
+	data := make([]float64, n*dims)
+	for i := range data {
+		data[i] = rand.Float64()
+	}
+	return data
+}
+
+// isFlatKDTree returns whether the rows [lo, hi) of t satisfy the k-d tree invariant.
+func (t *FlatTree) isFlatKDTree(lo, hi int, d Dim) bool {
+	if lo >= hi {
+		return true
+	}
+	mid := lo + (hi-lo)/2
+	v := t.point(mid)[d]
+	for i := lo; i < mid; i++ {
+		if t.point(i)[d] > v {
+			return false
+		}
+	}
+	for i := mid + 1; i < hi; i++ {
+		if t.point(i)[d] < v {
+			return false
+		}
+	}
+	d = (d + 1) % Dim(t.Dims)
+	return t.isFlatKDTree(lo, mid, d) && t.isFlatKDTree(mid+1, hi, d)
+}
+
+func (s *S) TestNewFlat(c *check.C) {
+	for _, dims := range []int{1, 2, 3, 7} {
+		for _, n := range []int{0, 1, 2, 3, 100, 1001} {
+			data := flatData(n, dims)
+			orig := append([]float64(nil), data...)
+			t := NewFlat(data, dims)
+			c.Check(t.Len(), check.Equals, n)
+			c.Check(t.isFlatKDTree(0, n, 0), check.Equals, true)
+			seen := make([]bool, n)
+			for i, j := range t.Index {
+				c.Check(seen[j], check.Equals, false)
+				seen[j] = true
+				c.Check([]float64(t.point(i)), check.DeepEquals, orig[j*dims:(j+1)*dims])
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		data []float64
+		dims int
+	}{
+		{data: make([]float64, 4), dims: 0},
+		{data: make([]float64, 4), dims: 3},
+	} {
+		c.Check(func() { NewFlat(test.data, test.dims) }, check.PanicMatches, "kdtree: invalid flat data shape")
+	}
+}
+
+func (s *S) TestFlatNearest(c *check.C) {
+	const (
+		n    = 1000
+		dims = 3
+	)
+	orig := flatData(n, dims)
+	t := NewFlat(append([]float64(nil), orig...), dims)
+	for i := 0; i < 100; i++ {
+		q := Point(flatData(1, dims))
+		dists := make([]float64, n)
+		for j := range dists {
+			dists[j] = q.Distance(Point(orig[j*dims : (j+1)*dims]))
+		}
+		order := make([]int, n)
+		for j := range order {
+			order[j] = j
+		}
+		sort.Slice(order, func(a, b int) bool { return dists[order[a]] < dists[order[b]] })
+
+		idx, d := t.Nearest(q)
+		c.Check(idx, check.Equals, order[0])
+		c.Check(d, check.Equals, dists[order[0]])
+
+		keep := NewNKeeper(10)
+		t.NearestSet(keep, q)
+		c.Assert(keep.Len(), check.Equals, 10)
+		for j, e := range keep.Heap {
+			p := e.Comparable.(FlatPoint)
+			c.Check(p.Index, check.Equals, order[j])
+			c.Check(e.Dist, check.Equals, dists[order[j]])
+			c.Check([]float64(p.Point), check.DeepEquals, orig[p.Index*dims:(p.Index+1)*dims])
+		}
+
+		r := dists[order[20]]
+		dk := NewDistKeeper(r)
+		t.NearestSet(dk, q)
+		c.Check(dk.Len(), check.Equals, 21)
+	}
+
+	e := NewFlat(nil, dims)
+	idx, d := e.Nearest(Point{0, 0, 0})
+	c.Check(idx, check.Equals, -1)
+	c.Check(d, check.Equals, math.Inf(1))
+	keep := NewNKeeper(3)
+	e.NearestSet(keep, Point{0, 0, 0})
+	c.Check(keep.Len(), check.Equals, 1)
+}
+
+func (s *S) TestFlatPoint(c *check.C) {
+	a := FlatPoint{Index: 1, Point: Point{1, 2}}
+	b := FlatPoint{Index: 4, Point: Point{4, 6}}
+	c.Check(a.Dims(), check.Equals, 2)
+	c.Check(a.Distance(b), check.Equals, 25.0)
+	c.Check(a.Distance(Point{4, 6}), check.Equals, 25.0)
+	c.Check(b.Compare(a, 1), check.Equals, 4.0)
+	c.Check(b.Compare(Point{1, 2}, 0), check.Equals, 3.0)
+}
+
+func BenchmarkFlatNearestSet(b *testing.B) {
+	const dims = 3
+	t := NewFlat(flatData(1e5, dims), dims)
+	queries := flatData(100, dims)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		j := i % 100
+		t.NearestSet(NewNKeeper(10), Point(queries[j*dims:(j+1)*dims]))
+	}
+}
+
+func BenchmarkTreeNearestSet(b *testing.B) {
+	t := New(randPoints(1e5, 3), false)
+	queries := randPoints(100, 3)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.NearestSet(NewNKeeper(10), queries[i%len(queries)])
+	}
+}
@@ -0,0 +1,448 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package generic implements a k-d tree over fixed-size arrays of coordinates.
+//
+// Unlike the kdtree package, coordinates are held in the nodes of the tree as array
+// values, so searches do not make interface method calls or type assertions to compare
+// points. Values of any type may be stored with their coordinates, which are obtained
+// from a coordinate accessor when the values are added to the tree.
+package generic
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Float is the constraint for coordinate types.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Array is the constraint for the coordinates of points in a Tree. Points may have
+// between one and eight dimensions.
+type Array[F Float] interface {
+	~[1]F | ~[2]F | ~[3]F | ~[4]F | ~[5]F | ~[6]F | ~[7]F | ~[8]F
+}
+
+// A Bounding represents a volume bounding box.
+type Bounding[F Float, A Array[F]] [2]A
+
+// Contains returns whether c is within the volume of the Bounding. A nil Bounding
+// returns true.
+func (b *Bounding[F, A]) Contains(c A) bool {
+	if b == nil {
+		return true
+	}
+	for d := 0; d < len(c); d++ {
+		if c[d] < b[0][d] || b[1][d] < c[d] {
+			return false
+		}
+	}
+	return true
+}
+
+// Distance returns the squared Euclidean distance between a and b.
+func Distance[F Float, A Array[F]](a, b A) float64 {
+	var sum float64
+	for d := 0; d < len(a); d++ {
+		c := float64(a[d]) - float64(b[d])
+		sum += c * c
+	}
+	return sum
+}
+
+// A Node holds a single point value in a k-d tree.
+type Node[F Float, A Array[F], V any] struct {
+	Coords A
+	Value  V
+	Plane  int
+	Left   *Node[F, A, V]
+	Right  *Node[F, A, V]
+}
+
+func (n *Node[F, A, V]) String() string {
+	if n == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%.3f %d", n.Coords, n.Plane)
+}
+
+// A Tree implements a k-d tree creation and nearest neighbour search.
+type Tree[F Float, A Array[F], V any] struct {
+	Root  *Node[F, A, V]
+	Count int
+
+	coords func(V) A
+}
+
+// New returns a k-d tree constructed from the values in p, with the coordinates of each
+// value given by coords. The coords function is retained for use by Insert.
+//
+// The coordinate type must be given explicitly; the array and value types are inferred:
+//
+//	t := generic.New[float64](cities, func(c City) [2]float64 { return c.Location })
+func New[F Float, A Array[F], V any](p []V, coords func(V) A) *Tree[F, A, V] {
+	nodes := make([]Node[F, A, V], len(p))
+	for i, v := range p {
+		nodes[i] = Node[F, A, V]{Coords: coords(v), Value: v}
+	}
+	return &Tree[F, A, V]{
+		Root:   build(nodes, 0),
+		Count:  len(p),
+		coords: coords,
+	}
+}
+
+// NewArrays returns a k-d tree constructed from the points in p. The stored values are
+// the points themselves.
+func NewArrays[F Float, A Array[F]](p []A) *Tree[F, A, A] {
+	return New[F](p, func(a A) A { return a })
+}
+
+// build links the nodes into a balanced tree splitting on plane at its root and returns
+// the root. The nodes are reordered by build.
+func build[F Float, A Array[F], V any](nodes []Node[F, A, V], plane int) *Node[F, A, V] {
+	if len(nodes) == 0 {
+		return nil
+	}
+	m := len(nodes) / 2
+	selectNth(nodes, m, plane)
+	n := &nodes[m]
+	n.Plane = plane
+	plane = (plane + 1) % len(n.Coords)
+	n.Left = build(nodes[:m], plane)
+	n.Right = build(nodes[m+1:], plane)
+	return n
+}
+
+// selectNth partially sorts nodes in dimension d such that the kth node is in its sorted
+// position, with no greater values before it and no lesser values after it.
+func selectNth[F Float, A Array[F], V any](nodes []Node[F, A, V], k, d int) {
+	lo, hi := 0, len(nodes)-1
+	for lo < hi {
+		// Use the median of the first, middle and
+		// last values as the partition pivot.
+		mid := lo + (hi-lo)/2
+		if nodes[mid].Coords[d] < nodes[lo].Coords[d] {
+			nodes[mid], nodes[lo] = nodes[lo], nodes[mid]
+		}
+		if nodes[hi].Coords[d] < nodes[lo].Coords[d] {
+			nodes[hi], nodes[lo] = nodes[lo], nodes[hi]
+		}
+		if nodes[hi].Coords[d] < nodes[mid].Coords[d] {
+			nodes[hi], nodes[mid] = nodes[mid], nodes[hi]
+		}
+		pivot := nodes[mid].Coords[d]
+
+		i, j := lo, hi
+		for i <= j {
+			for nodes[i].Coords[d] < pivot {
+				i++
+			}
+			for pivot < nodes[j].Coords[d] {
+				j--
+			}
+			if i <= j {
+				nodes[i], nodes[j] = nodes[j], nodes[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// Insert adds a value to the tree. Insert does not rebalance the tree. If the tree was not
+// constructed by New or NewArrays, the values must be the coordinate arrays themselves,
+// otherwise Insert panics.
+func (t *Tree[F, A, V]) Insert(v V) {
+	n := &Node[F, A, V]{Coords: t.coordsOf(v), Value: v}
+	t.Count++
+	if t.Root == nil {
+		t.Root = n
+		return
+	}
+	for p := t.Root; ; {
+		next := &p.Right
+		if n.Coords[p.Plane] <= p.Coords[p.Plane] {
+			next = &p.Left
+		}
+		if *next == nil {
+			n.Plane = (p.Plane + 1) % len(n.Coords)
+			*next = n
+			return
+		}
+		p = *next
+	}
+}
+
+// coordsOf returns the coordinates of v.
+func (t *Tree[F, A, V]) coordsOf(v V) A {
+	if t.coords != nil {
+		return t.coords(v)
+	}
+	a, ok := interface{}(v).(A)
+	if !ok {
+		panic("generic: no coordinate accessor")
+	}
+	return a
+}
+
+// Len returns the number of values in the tree.
+func (t *Tree[F, A, V]) Len() int { return t.Count }
+
+var inf = math.Inf(1)
+
+// Nearest returns the nearest value to the query and the squared Euclidean distance
+// between them. If the tree is empty, Nearest returns the zero value of V and infinite
+// distance.
+func (t *Tree[F, A, V]) Nearest(q A) (V, float64) {
+	n, dist := t.Root.search(q, inf)
+	if n == nil {
+		var v V
+		return v, inf
+	}
+	return n.Value, dist
+}
+
+func (n *Node[F, A, V]) search(q A, dist float64) (*Node[F, A, V], float64) {
+	if n == nil {
+		return nil, inf
+	}
+
+	var bn *Node[F, A, V]
+	if d := Distance[F](q, n.Coords); d < dist {
+		bn, dist = n, d
+	}
+	c := float64(q[n.Plane]) - float64(n.Coords[n.Plane])
+	near, far := n.Left, n.Right
+	if c > 0 {
+		near, far = far, near
+	}
+	if nn, nd := near.search(q, dist); nd < dist {
+		bn, dist = nn, nd
+	}
+	if c*c < dist {
+		if fn, fd := far.search(q, dist); fd < dist {
+			bn, dist = fn, fd
+		}
+	}
+	return bn, dist
+}
+
+// ValueDist holds a value and its distance to a specific query.
+type ValueDist[V any] struct {
+	Value V
+	Dist  float64
+}
+
+// Heap is a max heap sorted on Dist.
+type Heap[V any] []ValueDist[V]
+
+func (h *Heap[V]) Len() int             { return len(*h) }
+func (h *Heap[V]) Less(i, j int) bool   { return (*h)[i].Dist > (*h)[j].Dist }
+func (h *Heap[V]) Swap(i, j int)        { (*h)[i], (*h)[j] = (*h)[j], (*h)[i] }
+func (h *Heap[V]) Push(x interface{})   { (*h) = append(*h, x.(ValueDist[V])) }
+func (h *Heap[V]) Pop() (i interface{}) { i, *h = (*h)[len(*h)-1], (*h)[:len(*h)-1]; return i }
+
+// push adds c to the heap without boxing it in an interface value.
+func (h *Heap[V]) push(c ValueDist[V]) {
+	*h = append(*h, c)
+	for j := len(*h) - 1; j > 0; {
+		i := (j - 1) / 2
+		if !h.Less(j, i) {
+			break
+		}
+		h.Swap(i, j)
+		j = i
+	}
+}
+
+// replaceMax replaces the maximum value of the heap with c.
+func (h *Heap[V]) replaceMax(c ValueDist[V]) {
+	(*h)[0] = c
+	n := len(*h)
+	for i := 0; ; {
+		j := 2*i + 1
+		if j >= n {
+			break
+		}
+		if r := j + 1; r < n && h.Less(r, j) {
+			j = r
+		}
+		if !h.Less(j, i) {
+			break
+		}
+		h.Swap(i, j)
+		i = j
+	}
+}
+
+// NKeeper is a Keeper that retains the n best ValueDists that it is called to Keep.
+type NKeeper[V any] struct {
+	Heap[V]
+}
+
+// NewNKeeper returns an NKeeper that is able to retain at most n values. NewNKeeper
+// panics if n is less than one.
+func NewNKeeper[V any](n int) *NKeeper[V] {
+	if n < 1 {
+		panic("generic: invalid keeper size")
+	}
+	return &NKeeper[V]{make(Heap[V], 0, n)}
+}
+
+// Max returns the maximum retained value, or a value at infinite distance if fewer than
+// the maximum number of values have been retained.
+func (k *NKeeper[V]) Max() ValueDist[V] {
+	if len(k.Heap) < cap(k.Heap) {
+		return ValueDist[V]{Dist: inf}
+	}
+	return k.Heap[0]
+}
+
+// Keep add c to the heap if its distance is less than the maximum value of the heap. If adding
+// c would increase the size of the heap beyond the initial maximum length, the maximum value of
+// the heap is dropped.
+func (k *NKeeper[V]) Keep(c ValueDist[V]) {
+	switch {
+	case len(k.Heap) < cap(k.Heap):
+		k.push(c)
+	case c.Dist < k.Heap[0].Dist:
+		k.replaceMax(c)
+	}
+}
+
+// DistKeeper is a Keeper that retains the ValueDists within the specified distance of the
+// query that it is called to Keep.
+type DistKeeper[V any] struct {
+	Heap[V]
+	Dist float64
+}
+
+// NewDistKeeper returns an DistKeeper retaining values within distance d.
+func NewDistKeeper[V any](d float64) *DistKeeper[V] { return &DistKeeper[V]{Dist: d} }
+
+// Max returns a value at the distance of the DistKeeper.
+func (k *DistKeeper[V]) Max() ValueDist[V] { return ValueDist[V]{Dist: k.Dist} }
+
+// Keep adds c to the heap if its distance is less than or equal to the distance of the
+// DistKeeper.
+func (k *DistKeeper[V]) Keep(c ValueDist[V]) {
+	if c.Dist <= k.Dist {
+		k.push(c)
+	}
+}
+
+// Keeper implements a conditional max heap sorted on the Dist field of the ValueDist type.
+// kd search is guided by the distance returned by the Max method of the Keeper.
+type Keeper[V any] interface {
+	Keep(ValueDist[V]) // Keep conditionally pushes the provided ValueDist onto the heap.
+	Max() ValueDist[V] // Max returns the maximum acceptable ValueDist of the Keeper.
+	heap.Interface
+}
+
+// NearestSet finds the nearest values to the query accepted by the provided Keeper, k.
+// k must be able to return a ValueDist specifying the maximum acceptable distance when
+// Max() is called, and retains the results of the search in min sorted order after the
+// call to NearestSet returns.
+func (t *Tree[F, A, V]) NearestSet(k Keeper[V], q A) {
+	if t.Root == nil {
+		return
+	}
+	t.Root.searchSet(q, k)
+	sort.Sort(sort.Reverse(k))
+}
+
+func (n *Node[F, A, V]) searchSet(q A, k Keeper[V]) {
+	if n == nil {
+		return
+	}
+
+	if d := Distance[F](q, n.Coords); d <= k.Max().Dist {
+		k.Keep(ValueDist[V]{Value: n.Value, Dist: d})
+	}
+	c := float64(q[n.Plane]) - float64(n.Coords[n.Plane])
+	near, far := n.Left, n.Right
+	if c > 0 {
+		near, far = far, near
+	}
+	near.searchSet(q, k)
+	if c*c <= k.Max().Dist {
+		far.searchSet(q, k)
+	}
+}
+
+// An Operation is a function that operates on a value and its coordinates. The tree depth
+// of the value is also provided. If done is returned true, the Operation is indicating that
+// no further work needs to be done and so the Do function should traverse no further.
+type Operation[F Float, A Array[F], V any] func(A, V, int) (done bool)
+
+// Do performs fn on all values stored in the tree. A boolean is returned indicating whether the
+// Do traversal was interrupted by an Operation returning true.
+func (t *Tree[F, A, V]) Do(fn Operation[F, A, V]) bool {
+	if t.Root == nil {
+		return false
+	}
+	return t.Root.do(fn, 0)
+}
+
+func (n *Node[F, A, V]) do(fn Operation[F, A, V], depth int) (done bool) {
+	if n.Left != nil {
+		done = n.Left.do(fn, depth+1)
+		if done {
+			return
+		}
+	}
+	done = fn(n.Coords, n.Value, depth)
+	if done {
+		return
+	}
+	if n.Right != nil {
+		done = n.Right.do(fn, depth+1)
+	}
+	return
+}
+
+// DoBounded performs fn on all values stored in the tree that are within the specified bound.
+// If b is nil, the result is the same as a Do. A boolean is returned indicating whether the
+// DoBounded traversal was interrupted by an Operation returning true.
+func (t *Tree[F, A, V]) DoBounded(fn Operation[F, A, V], b *Bounding[F, A]) bool {
+	if t.Root == nil {
+		return false
+	}
+	if b == nil {
+		return t.Root.do(fn, 0)
+	}
+	return t.Root.doBounded(fn, b, 0)
+}
+
+func (n *Node[F, A, V]) doBounded(fn Operation[F, A, V], b *Bounding[F, A], depth int) (done bool) {
+	if n.Left != nil && b[0][n.Plane] <= n.Coords[n.Plane] {
+		done = n.Left.doBounded(fn, b, depth+1)
+		if done {
+			return
+		}
+	}
+	if b.Contains(n.Coords) {
+		done = fn(n.Coords, n.Value, depth)
+		if done {
+			return
+		}
+	}
+	if n.Right != nil && n.Coords[n.Plane] <= b[1][n.Plane] {
+		done = n.Right.doBounded(fn, b, depth+1)
+	}
+	return
+}
@@ -0,0 +1,291 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package generic
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"gopkg.in/check.v1"
+
+	"github.com/biogo/store/kdtree"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+// cell is a user type with a coordinate accessor.
+type cell struct {
+	id  int
+	x   float32
+	y   float32
+	z   float32
+	tag string
+}
+
+func (c cell) coords() [3]float32 { return [3]float32{c.x, c.y, c.z} }
+
+func randArrays(n int) [][2]float64 {
+	p := make([][2]float64, n)
+	for i := range p {
+		p[i] = [2]float64{rand.Float64(), rand.Float64()}
+	}
+	return p
+}
+
+func randCells(n int) []cell {
+	p := make([]cell, n)
+	for i := range p {
+		p[i] = cell{id: i, x: rand.Float32(), y: rand.Float32(), z: float32(rand.Intn(10))}
+	}
+	return p
+}
+
+// isKDTree returns whether the subtree rooted at n satisfies the k-d tree invariant.
+func (n *Node[F, A, V]) isKDTree() bool {
+	if n == nil {
+		return true
+	}
+	ok := true
+	check := func(m *Node[F, A, V], left bool) {
+		if m == nil {
+			return
+		}
+		m.do(func(c A, _ V, _ int) (done bool) {
+			if left && c[n.Plane] > n.Coords[n.Plane] || !left && c[n.Plane] < n.Coords[n.Plane] {
+				ok = false
+			}
+			return !ok
+		}, 0)
+	}
+	check(n.Left, true)
+	check(n.Right, false)
+	return ok && n.Left.isKDTree() && n.Right.isKDTree()
+}
+
+// sortedDists returns the distances from q to each of the points in p in ascending order.
+func sortedDists[F Float, A Array[F]](q A, p []A) []float64 {
+	d := make([]float64, len(p))
+	for i, v := range p {
+		d[i] = Distance[F](q, v)
+	}
+	sort.Float64s(d)
+	return d
+}
+
+func (s *S) TestNew(c *check.C) {
+	for _, n := range []int{0, 1, 2, 3, 10, 1000} {
+		p := randArrays(n)
+		t := NewArrays[float64](append([][2]float64(nil), p...))
+		c.Check(t.Len(), check.Equals, n)
+		c.Check(t.Root.isKDTree(), check.Equals, true)
+		var got [][2]float64
+		t.Do(func(a [2]float64, v [2]float64, _ int) (done bool) {
+			c.Check(a, check.Equals, v)
+			got = append(got, v)
+			return
+		})
+		c.Check(len(got), check.Equals, n)
+	}
+
+	// Ties in the splitting planes.
+	cells := randCells(1000)
+	t := New[float32](cells, cell.coords)
+	c.Check(t.Len(), check.Equals, len(cells))
+	c.Check(t.Root.isKDTree(), check.Equals, true)
+	ids := make([]int, 0, len(cells))
+	t.Do(func(a [3]float32, v cell, _ int) (done bool) {
+		c.Check(a, check.Equals, v.coords())
+		ids = append(ids, v.id)
+		return
+	})
+	sort.Ints(ids)
+	for i, id := range ids {
+		c.Check(id, check.Equals, i)
+	}
+}
+
+func (s *S) TestInsert(c *check.C) {
+	cells := randCells(1000)
+	t := New[float32](append([]cell(nil), cells[:10]...), cell.coords)
+	for _, v := range cells[10:] {
+		t.Insert(v)
+	}
+	c.Check(t.Len(), check.Equals, len(cells))
+	c.Check(t.Root.isKDTree(), check.Equals, true)
+	for _, v := range cells {
+		got, d := t.Nearest(v.coords())
+		c.Check(d, check.Equals, 0.0)
+		c.Check(got.coords(), check.Equals, v.coords())
+	}
+
+	var a Tree[float64, [2]float64, [2]float64]
+	for _, v := range randArrays(100) {
+		a.Insert(v)
+	}
+	c.Check(a.Len(), check.Equals, 100)
+	c.Check(a.Root.isKDTree(), check.Equals, true)
+
+	var e Tree[float64, [2]float64, int]
+	c.Check(func() { e.Insert(1) }, check.PanicMatches, "generic: no coordinate accessor")
+}
+
+func (s *S) TestNearest(c *check.C) {
+	p := randArrays(1000)
+	t := NewArrays[float64](append([][2]float64(nil), p...))
+	kp := make(kdtree.Points, len(p))
+	for i, v := range p {
+		kp[i] = kdtree.Point{v[0], v[1]}
+	}
+	kt := kdtree.New(kp, false)
+	for i := 0; i < 100; i++ {
+		q := randArrays(1)[0]
+		want := sortedDists[float64](q, p)
+
+		got, d := t.Nearest(q)
+		c.Check(d, check.Equals, want[0])
+		c.Check(Distance[float64](q, got), check.Equals, d)
+
+		nk := NewNKeeper[[2]float64](10)
+		t.NearestSet(nk, q)
+		c.Assert(nk.Len(), check.Equals, 10)
+		for j, e := range nk.Heap {
+			c.Check(e.Dist, check.Equals, want[j])
+			c.Check(Distance[float64](q, e.Value), check.Equals, e.Dist)
+		}
+
+		r := want[20]
+		dk := NewDistKeeper[[2]float64](r)
+		t.NearestSet(dk, q)
+		c.Assert(dk.Len(), check.Equals, 21)
+		for j, e := range dk.Heap {
+			c.Check(e.Dist, check.Equals, want[j])
+		}
+
+		// Agreement with kdtree.
+		_, kd := kt.Nearest(kdtree.Point{q[0], q[1]})
+		c.Check(d, check.Equals, kd)
+	}
+
+	// Small keepers and trees.
+	small := NewArrays[float64](p[:3])
+	nk := NewNKeeper[[2]float64](10)
+	small.NearestSet(nk, [2]float64{})
+	c.Check(nk.Len(), check.Equals, 3)
+	c.Check(sort.IsSorted(sort.Reverse(nk)), check.Equals, true)
+
+	var e Tree[float64, [2]float64, int]
+	v, d := e.Nearest([2]float64{})
+	c.Check(v, check.Equals, 0)
+	c.Check(d, check.Equals, math.Inf(1))
+	c.Check(func() { NewNKeeper[int](0) }, check.PanicMatches, "generic: invalid keeper size")
+}
+
+func (s *S) TestDoBounded(c *check.C) {
+	cells := randCells(1000)
+	t := New[float32](append([]cell(nil), cells...), cell.coords)
+	for i := 0; i < 50; i++ {
+		min := [3]float32{rand.Float32() / 2, rand.Float32() / 2, float32(rand.Intn(5))}
+		max := [3]float32{min[0] + 0.3, min[1] + 0.3, min[2] + 3}
+		b := &Bounding[float32, [3]float32]{min, max}
+
+		var want []int
+		for _, v := range cells {
+			if b.Contains(v.coords()) {
+				want = append(want, v.id)
+			}
+		}
+		var got []int
+		c.Check(t.DoBounded(func(_ [3]float32, v cell, _ int) (done bool) {
+			got = append(got, v.id)
+			return
+		}, b), check.Equals, false)
+		sort.Ints(want)
+		sort.Ints(got)
+		c.Check(got, check.DeepEquals, want)
+	}
+
+	var n int
+	c.Check(t.DoBounded(func([3]float32, cell, int) bool { n++; return n == 5 }, nil), check.Equals, true)
+	c.Check(n, check.Equals, 5)
+	var e Tree[float64, [2]float64, int]
+	c.Check(e.Do(func([2]float64, int, int) bool { return true }), check.Equals, false)
+	c.Check(e.DoBounded(func([2]float64, int, int) bool { return true }, nil), check.Equals, false)
+}
+
+var (
+	benchData    = randArrays(1e5)
+	benchQueries = randArrays(100)
+)
+
+func BenchmarkNew(b *testing.B) {
+	p := make([][2]float64, len(benchData))
+	for i := 0; i < b.N; i++ {
+		copy(p, benchData)
+		NewArrays[float64](p)
+	}
+}
+
+func BenchmarkNewKDTree(b *testing.B) {
+	data := make(kdtree.Points, len(benchData))
+	for i, v := range benchData {
+		data[i] = kdtree.Point{v[0], v[1]}
+	}
+	p := make(kdtree.Points, len(data))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(p, data)
+		kdtree.New(p, false)
+	}
+}
+
+func BenchmarkNearest(b *testing.B) {
+	t := NewArrays[float64](append([][2]float64(nil), benchData...))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.Nearest(benchQueries[i%len(benchQueries)])
+	}
+}
+
+func BenchmarkNearestKDTree(b *testing.B) {
+	t, queries := kdTree()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.Nearest(queries[i%len(queries)])
+	}
+}
+
+func BenchmarkNearestSet(b *testing.B) {
+	t := NewArrays[float64](append([][2]float64(nil), benchData...))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.NearestSet(NewNKeeper[[2]float64](10), benchQueries[i%len(benchQueries)])
+	}
+}
+
+func BenchmarkNearestSetKDTree(b *testing.B) {
+	t, queries := kdTree()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.NearestSet(kdtree.NewNKeeper(10), queries[i%len(queries)])
+	}
+}
+
+func kdTree() (*kdtree.Tree, []kdtree.Point) {
+	p := make(kdtree.Points, len(benchData))
+	for i, v := range benchData {
+		p[i] = kdtree.Point{v[0], v[1]}
+	}
+	q := make([]kdtree.Point, len(benchQueries))
+	for i, v := range benchQueries {
+		q[i] = kdtree.Point{v[0], v[1]}
+	}
+	return kdtree.New(p, false), q
+}
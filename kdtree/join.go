@@ -0,0 +1,251 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import "math"
+
+// A PairOperation is a function that operates on a pair of Comparables and the distance
+// between them. If done is returned true, the PairOperation is indicating that no further
+// work needs to be done and so the calling function should traverse no further.
+type PairOperation func(p, q Comparable, dist float64) (done bool)
+
+// PairsWithin performs fn on all pairs of values p in a and q in b that are within
+// distance r of each other, where distance is measured by the Distance method of p. If
+// both trees have bounding volumes, pairs of subtrees are pruned using their volumes,
+// otherwise each value in a is searched for in b. A boolean is returned indicating
+// whether the traversal was interrupted by a PairOperation returning true.
+//
+// The dual-tree approach is described in 'N-body problems in statistical learning.'
+// A. G. Gray and A. W. Moore Advances in Neural Information Processing Systems 13.
+func PairsWithin(a, b *Tree, r float64, fn PairOperation) bool {
+	if a.Root == nil || b.Root == nil {
+		return false
+	}
+	if a.Root.Bounding == nil || b.Root.Bounding == nil {
+		return a.Do(func(p Comparable, _ *Bounding, _ int) (done bool) {
+			return b.Root.within(p, r, func(q Comparable, dist float64) bool { return fn(p, q, dist) })
+		})
+	}
+	return a.Root.pairsWithin(b.Root, r, fn)
+}
+
+func (n *Node) pairsWithin(o *Node, r float64, fn PairOperation) (done bool) {
+	if n == nil || o == nil || n.Bounding.gap(o.Bounding) > r {
+		return false
+	}
+	p := n.Point
+	if o.within(p, r, func(q Comparable, dist float64) bool { return fn(p, q, dist) }) {
+		return true
+	}
+	for _, c := range [2]*Node{n.Left, n.Right} {
+		if c == nil {
+			continue
+		}
+		q := o.Point
+		if c.within(q, r, func(p Comparable, dist float64) bool { return fn(p, q, dist) }) {
+			return true
+		}
+		if c.pairsWithin(o.Left, r, fn) || c.pairsWithin(o.Right, r, fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// within performs fn on all values in the subtree rooted at n that are within distance
+// r of q, where distance is measured by the Distance method of q.
+func (n *Node) within(q Comparable, r float64, fn func(Comparable, float64) bool) (done bool) {
+	if n == nil {
+		return false
+	}
+	if n.Bounding != nil {
+		if near, _ := n.Bounding.distances(q); near > r {
+			return false
+		}
+	}
+	if d := q.Distance(n.Point); d <= r && fn(n.Point, d) {
+		return true
+	}
+	c := q.Compare(n.Point, n.Plane)
+	if c <= 0 {
+		return n.Left.within(q, r, fn) || (c*c <= r && n.Right.within(q, r, fn))
+	}
+	return n.Right.within(q, r, fn) || (c*c <= r && n.Left.within(q, r, fn))
+}
+
+// gap returns the squared Euclidean distance between the nearest points of the volumes
+// b and o. If either volume is nil, gap returns zero.
+func (b *Bounding) gap(o *Bounding) float64 {
+	if b == nil || o == nil {
+		return 0
+	}
+	var dist float64
+	for d := Dim(0); d < Dim(b[0].Dims()); d++ {
+		if c := o[0].Compare(b[1], d); c > 0 {
+			dist += c * c
+		} else if c := b[0].Compare(o[1], d); c > 0 {
+			dist += c * c
+		}
+	}
+	return dist
+}
+
+// KNN holds a Comparable and its nearest neighbours in ascending order of distance.
+type KNN struct {
+	Comparable Comparable
+	Neighbours []ComparableDist
+}
+
+// KNNJoin returns the k nearest neighbours in b of each value in a, in the order the
+// values of a are visited by Do. Distance is measured by the Distance method of the
+// values in a. If both trees have bounding volumes, pairs of subtrees are pruned using
+// their volumes, otherwise each value in a is searched for in b.
+func KNNJoin(a, b *Tree, k int) []KNN {
+	if a.Root == nil {
+		return nil
+	}
+	if k < 1 {
+		knn := make([]KNN, 0, a.Len())
+		a.Do(func(p Comparable, _ *Bounding, _ int) (done bool) {
+			knn = append(knn, KNN{Comparable: p})
+			return
+		})
+		return knn
+	}
+	j := newKNNJoin(a.Root, k)
+	if b.Root != nil {
+		if a.Root.Bounding == nil || b.Root.Bounding == nil {
+			j.walk(func(q *knnJoin) { b.Root.searchSet(q.Point, Euclidean{}, q.keep) })
+		} else {
+			j.join(b.Root)
+		}
+	}
+
+	knn := make([]KNN, 0, a.Len())
+	j.walk(func(q *knnJoin) {
+		finish(q.keep)
+		knn = append(knn, KNN{Comparable: q.Point, Neighbours: q.keep.Heap})
+	})
+	return knn
+}
+
+// knnJoin mirrors a query tree node, holding the state of a dual-tree k nearest
+// neighbour join.
+type knnJoin struct {
+	*Node
+	left, right *knnJoin
+
+	// keep holds the neighbours found for the point of the node.
+	keep *NKeeper
+
+	// bound is an upper bound on the squared distance to the kth
+	// neighbour of all the points in the subtree rooted at the node.
+	bound float64
+
+	// diam is the Euclidean diameter of the node's bounding volume.
+	diam float64
+}
+
+func newKNNJoin(n *Node, k int) *knnJoin {
+	if n == nil {
+		return nil
+	}
+	j := &knnJoin{
+		Node:  n,
+		left:  newKNNJoin(n.Left, k),
+		right: newKNNJoin(n.Right, k),
+		keep:  NewNKeeper(k),
+		bound: inf,
+		diam:  inf,
+	}
+	if n.Bounding != nil {
+		var d2 float64
+		for d := Dim(0); d < Dim(n.Bounding[0].Dims()); d++ {
+			c := n.Bounding[1].Compare(n.Bounding[0], d)
+			d2 += c * c
+		}
+		j.diam = math.Sqrt(d2)
+	}
+	return j
+}
+
+// joinRatio is the factor by which a reference subtree must outnumber a query
+// subtree before the reference subtree is split during a KNNJoin. Searches from
+// single query points are cheaper than offers to query subtrees, so splitting
+// of the reference tree is deferred until it is well worth while.
+const joinRatio = 256
+
+// join finds neighbours in the subtree rooted at o for the points in the subtree
+// rooted at j.
+func (j *knnJoin) join(o *Node) {
+	if j == nil || o == nil || j.Bounding.gap(o.Bounding) > j.bound {
+		return
+	}
+	if j.Size*joinRatio >= o.Size {
+		o.searchSet(j.Point, Euclidean{}, j.keep)
+		j.update()
+		j.left.join(o)
+		j.right.join(o)
+	} else {
+		near, far := o.Left, o.Right
+		if far != nil && (near == nil || j.Bounding.gap(far.Bounding) < j.Bounding.gap(near.Bounding)) {
+			near, far = far, near
+		}
+		j.join(near)
+		j.offer(o.Point)
+		j.join(far)
+	}
+	j.update()
+}
+
+// offer offers q as a neighbour to the points in the subtree rooted at j.
+func (j *knnJoin) offer(q Comparable) {
+	if j == nil {
+		return
+	}
+	if j.Bounding != nil {
+		if near, _ := j.Bounding.distances(q); near > j.bound {
+			return
+		}
+	}
+	j.keep.Keep(ComparableDist{Comparable: q, Dist: j.Point.Distance(q)})
+	j.left.offer(q)
+	j.right.offer(q)
+	j.update()
+}
+
+// update recalculates the neighbour distance bound of j. The bound is the
+// lesser of the greatest bound held in the subtree and the bound implied by
+// the triangle inequality from the kth neighbour of j's point.
+func (j *knnJoin) update() {
+	own := j.keep.Max().Dist
+	b := own
+	for _, c := range [2]*knnJoin{j.left, j.right} {
+		if c != nil {
+			b = math.Max(b, c.bound)
+		}
+	}
+	if r := math.Sqrt(own) + j.diam; r*r < b {
+		b = r * r
+	}
+	if b < j.bound {
+		j.bound = b
+	}
+	for _, c := range [2]*knnJoin{j.left, j.right} {
+		if c != nil && j.bound < c.bound {
+			c.bound = j.bound
+		}
+	}
+}
+
+// walk performs fn on each node of the subtree rooted at j in order.
+func (j *knnJoin) walk(fn func(*knnJoin)) {
+	if j == nil {
+		return
+	}
+	j.left.walk(fn)
+	fn(j)
+	j.right.walk(fn)
+}
@@ -0,0 +1,126 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"sort"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+type pointPair struct {
+	p, q Point
+	dist float64
+}
+
+func sortPairs(pairs []pointPair) []pointPair {
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i], pairs[j]
+		for d := range a.p {
+			if a.p[d] != b.p[d] {
+				return a.p[d] < b.p[d]
+			}
+		}
+		for d := range a.q {
+			if a.q[d] != b.q[d] {
+				return a.q[d] < b.q[d]
+			}
+		}
+		return false
+	})
+	return pairs
+}
+
+func (s *S) TestPairsWithin(c *check.C) {
+	const r = 0.01
+	a, b := randPoints(500, 3), randPoints(700, 3)
+	var want []pointPair
+	for _, p := range a {
+		for _, q := range b {
+			if d := p.Distance(q); d <= r {
+				want = append(want, pointPair{p, q, d})
+			}
+		}
+	}
+	c.Assert(len(want) > 0, check.Equals, true)
+	sortPairs(want)
+
+	for _, bounding := range [][2]bool{{true, true}, {true, false}, {false, false}} {
+		ta := New(append(Points(nil), a...), bounding[0])
+		tb := New(append(Points(nil), b...), bounding[1])
+		var got []pointPair
+		c.Check(PairsWithin(ta, tb, r, func(p, q Comparable, dist float64) (done bool) {
+			got = append(got, pointPair{p.(Point), q.(Point), dist})
+			return
+		}), check.Equals, false)
+		c.Check(sortPairs(got), check.DeepEquals, want, check.Commentf("bounding=%v", bounding))
+
+		var n int
+		c.Check(PairsWithin(ta, tb, r, func(_, _ Comparable, _ float64) (done bool) {
+			n++
+			return n == 3
+		}), check.Equals, true)
+		c.Check(n, check.Equals, 3)
+	}
+	c.Check(PairsWithin(&Tree{}, New(b, true), r, nil), check.Equals, false)
+}
+
+func (s *S) TestKNNJoin(c *check.C) {
+	const k = 4
+	a, b := randPoints(500, 3), randPoints(700, 3)
+	for _, bounding := range [][2]bool{{true, true}, {false, true}, {false, false}} {
+		ta := New(append(Points(nil), a...), bounding[0])
+		tb := New(append(Points(nil), b...), bounding[1])
+		knn := KNNJoin(ta, tb, k)
+		c.Assert(len(knn), check.Equals, len(a))
+		var i int
+		ta.Do(func(p Comparable, _ *Bounding, _ int) (done bool) {
+			c.Check(knn[i].Comparable, check.DeepEquals, p)
+			want := nearestN(k, p.(Point), b)
+			c.Check(knn[i].Neighbours, check.DeepEquals, want, check.Commentf("bounding=%v", bounding))
+			i++
+			return
+		})
+	}
+
+	// A small query tree causes the reference tree to be split.
+	few := a[:3]
+	knn := KNNJoin(New(append(Points(nil), few...), true), New(append(Points(nil), b...), true), k)
+	for _, e := range knn {
+		c.Check(e.Neighbours, check.DeepEquals, nearestN(k, e.Comparable.(Point), b))
+	}
+
+	small := KNNJoin(New(a[:5], true), New(b[:2], true), k)
+	for _, e := range small {
+		c.Check(len(e.Neighbours), check.Equals, 2)
+	}
+	for _, e := range KNNJoin(New(a[:5], true), &Tree{}, k) {
+		c.Check(len(e.Neighbours), check.Equals, 0)
+	}
+	for _, e := range KNNJoin(New(a[:5], true), New(b, true), 0) {
+		c.Check(e.Neighbours, check.IsNil)
+	}
+	c.Check(KNNJoin(&Tree{}, New(b, true), k), check.IsNil)
+}
+
+func BenchmarkKNNJoin(b *testing.B) {
+	ta, tb := New(randPoints(1e4, 3), true), New(randPoints(1e4, 3), true)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		KNNJoin(ta, tb, 10)
+	}
+}
+
+func BenchmarkKNNJoinSingle(b *testing.B) {
+	ta, tb := New(randPoints(1e4, 3), true), New(randPoints(1e4, 3), true)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ta.Do(func(p Comparable, _ *Bounding, _ int) (done bool) {
+			tb.NearestSet(NewNKeeper(10), p)
+			return
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"unsafe"
 )
 
 type Interface interface {
@@ -90,6 +91,17 @@ type Node struct {
 	Plane       Dim
 	Left, Right *Node
 	*Bounding
+
+	// Size is the number of nodes in the
+	// subtree rooted at the Node. Size is
+	// maintained by the Tree methods, and
+	// clients that construct or alter nodes
+	// directly must keep it correct, since
+	// it is used to count whole subtrees and
+	// to find unbalanced subtrees. CountWithin
+	// and CountBounded count the nodes of a
+	// subtree that has a zero Size.
+	Size int
 }
 
 func (n *Node) String() string {
@@ -103,6 +115,14 @@ func (n *Node) String() string {
 type Tree struct {
 	Root  *Node
 	Count int
+
+	// Alpha is the weight balance factor used to rebuild
+	// unbalanced subtrees after an Insert. If Alpha is in
+	// the range (0.5, 1), a subtree is rebuilt when the
+	// insertion depth exceeds log_{1/Alpha}(Count) and one
+	// of its children holds more than Alpha of its nodes.
+	// Otherwise no rebalancing is performed by Insert.
+	Alpha float64
 }
 
 // New returns a k-d tree constructed from the values in p. If p is a Bounder and
@@ -135,6 +155,7 @@ func build(p Interface, plane Dim) *Node {
 		Left:     build(p.Slice(0, piv), np),
 		Right:    build(p.Slice(piv+1, p.Len()), np),
 		Bounding: nil,
+		Size:     p.Len(),
 	}
 }
 
@@ -154,25 +175,30 @@ func buildBounded(p bounder, plane Dim, bounding bool) *Node {
 		Left:     buildBounded(p.Slice(0, piv).(bounder), np, bounding),
 		Right:    buildBounded(p.Slice(piv+1, p.Len()).(bounder), np, bounding),
 		Bounding: b,
+		Size:     p.Len(),
 	}
 }
 
 // Insert adds a point to the tree, updating the bounding volumes if bounding is
 // true, and the tree is empty or the tree already has bounding volumes stored,
-// and c is an Extender. No rebalancing of the tree is performed.
+// and c is an Extender. The tree is rebalanced as described for the Alpha field.
 func (t *Tree) Insert(c Comparable, bounding bool) {
 	t.Count++
 	if t.Root != nil {
 		bounding = t.Root.Bounding != nil
 	}
-	if c, ok := c.(Extender); ok && bounding {
-		t.Root = t.Root.insertBounded(c, 0, bounding)
-		return
-	} else if !ok && t.Root != nil {
-		// If we are not rebounding, mark the tree as non-bounded.
-		t.Root.Bounding = nil
+	if e, ok := c.(Extender); ok && bounding {
+		t.Root = t.Root.insertBounded(e, 0, bounding)
+	} else {
+		if !ok && t.Root != nil {
+			// If we are not rebounding, mark the tree as non-bounded.
+			t.Root.Bounding = nil
+		}
+		t.Root = t.Root.insert(c, 0)
+	}
+	if 0.5 < t.Alpha && t.Alpha < 1 {
+		t.rebalanceAfter(c)
 	}
-	t.Root = t.Root.insert(c, 0)
 }
 
 func (n *Node) insert(c Comparable, d Dim) *Node {
@@ -181,9 +207,11 @@ func (n *Node) insert(c Comparable, d Dim) *Node {
 			Point:    c,
 			Plane:    d,
 			Bounding: nil,
+			Size:     1,
 		}
 	}
 
+	n.Size++
 	d = (n.Plane + 1) % Dim(c.Dims())
 	if c.Compare(n.Point, n.Plane) <= 0 {
 		n.Left = n.Left.insert(c, d)
@@ -204,9 +232,11 @@ func (n *Node) insertBounded(c Extender, d Dim, bounding bool) *Node {
 			Point:    c,
 			Plane:    d,
 			Bounding: b,
+			Size:     1,
 		}
 	}
 
+	n.Size++
 	if bounding {
 		n.Bounding = c.Extend(n.Bounding)
 	}
@@ -220,9 +250,182 @@ func (n *Node) insertBounded(c Extender, d Dim, bounding bool) *Node {
 	return n
 }
 
+// Delete removes a point with the same coordinates as c from the tree, returning whether
+// a point was removed. The removed node is replaced by the point with the minimum value
+// in the node's plane from its subtree. If the tree has bounding volumes, the volumes of
+// the ancestors of the removed point are updated if all the stored points and bounding
+// volume corners are Extenders, otherwise the tree is marked as non-bounded. No
+// rebalancing of the tree is performed.
+func (t *Tree) Delete(c Comparable) bool {
+	if t.Root == nil {
+		return false
+	}
+	var ok bool
+	t.Root, ok = t.Root.delete(c, nil, t.Root.Bounding != nil)
+	if ok {
+		t.Count--
+	}
+	return ok
+}
+
+// delete removes the node holding a point with the same coordinates as c from the subtree
+// rooted at n, returning the new root of the subtree and whether a node was removed. If
+// target is not nil, only the target node is removed.
+func (n *Node) delete(c Comparable, target *Node, bounding bool) (*Node, bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	if n == target || (target == nil && sameCoordinates(c, n.Point)) {
+		switch {
+		case n.Right != nil:
+			m := n.Right.min(n.Plane)
+			n.Point = m.Point
+			n.Right, _ = n.Right.delete(m.Point, m, bounding)
+		case n.Left != nil:
+			m := n.Left.min(n.Plane)
+			n.Point = m.Point
+			n.Right, _ = n.Left.delete(m.Point, m, bounding)
+			n.Left = nil
+		default:
+			return nil, true
+		}
+		n.Size--
+		if bounding {
+			n.bound()
+		}
+		return n, true
+	}
+
+	var ok bool
+	cmp := c.Compare(n.Point, n.Plane)
+	if cmp <= 0 {
+		n.Left, ok = n.Left.delete(c, target, bounding)
+	}
+	if !ok && cmp >= 0 {
+		n.Right, ok = n.Right.delete(c, target, bounding)
+	}
+	if ok {
+		n.Size--
+		if bounding {
+			n.bound()
+		}
+	}
+	return n, ok
+}
+
+// min returns the node in the subtree rooted at n with the minimum value in dimension d.
+func (n *Node) min(d Dim) *Node {
+	if n == nil {
+		return nil
+	}
+	m := n
+	if l := n.Left.min(d); l != nil && l.Point.Compare(m.Point, d) < 0 {
+		m = l
+	}
+	if n.Plane == d {
+		return m
+	}
+	if r := n.Right.min(d); r != nil && r.Point.Compare(m.Point, d) < 0 {
+		m = r
+	}
+	return m
+}
+
+// bound sets the bounding volume of n to the volume containing the point of n and the
+// bounding volumes of its children. If the volume cannot be constructed the bounding
+// volume of n is set to nil.
+func (n *Node) bound() {
+	n.Bounding = nil
+	e, ok := n.Point.(Extender)
+	if !ok {
+		return
+	}
+	b := e.Extend(nil)
+	for _, ch := range [2]*Node{n.Left, n.Right} {
+		if ch == nil {
+			continue
+		}
+		if ch.Bounding == nil {
+			return
+		}
+		for _, v := range ch.Bounding {
+			e, ok := v.(Extender)
+			if !ok {
+				return
+			}
+			b = e.Extend(b)
+		}
+	}
+	n.Bounding = b
+}
+
+// sameCoordinates returns whether a and b have the same coordinates.
+func sameCoordinates(a, b Comparable) bool {
+	for d := Dim(0); d < Dim(a.Dims()); d++ {
+		if a.Compare(b, d) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // Len returns the number of elements in the tree.
 func (t *Tree) Len() int { return t.Count }
 
+// Stats holds summary statistics describing the shape of a tree.
+type Stats struct {
+	Nodes     int     // Number of nodes in the tree.
+	Leaves    int     // Number of nodes without children.
+	Height    int     // Number of nodes on the longest path from the root to a leaf.
+	MeanDepth float64 // Mean depth of nodes, where the root has depth zero.
+	Bytes     int     // Estimated bytes used by nodes and bounding boxes, excluding stored points.
+
+	// Splits holds the number of nodes splitting on each dimension.
+	Splits []int
+
+	// Imbalance is the ratio of the height of the tree to the height of a
+	// perfectly balanced tree with the same number of nodes. A value of 1
+	// indicates that the tree is perfectly balanced.
+	Imbalance float64
+}
+
+// Stats returns summary statistics for the Tree.
+func (t *Tree) Stats() Stats {
+	var s Stats
+	if t.Root == nil {
+		return s
+	}
+	s.Splits = make([]int, t.Root.Point.Dims())
+	s.Height = t.Root.stats(&s, 0)
+	s.MeanDepth /= float64(s.Nodes)
+	s.Imbalance = float64(s.Height) / math.Ceil(math.Log2(float64(s.Nodes+1)))
+	return s
+}
+
+// stats accumulates node counts and the sum of node depths into s, returning
+// the height of the subtree rooted at n.
+func (n *Node) stats(s *Stats, depth int) (height int) {
+	if n == nil {
+		return 0
+	}
+	s.Nodes++
+	s.MeanDepth += float64(depth)
+	s.Splits[n.Plane]++
+	s.Bytes += int(unsafe.Sizeof(*n))
+	if n.Bounding != nil {
+		s.Bytes += int(unsafe.Sizeof(*n.Bounding))
+	}
+	if n.Left == nil && n.Right == nil {
+		s.Leaves++
+	}
+	height = n.Left.stats(s, depth+1)
+	if h := n.Right.stats(s, depth+1); h > height {
+		height = h
+	}
+	return height + 1
+}
+
 // Contains returns whether a Comparable is in the bounds of the tree. If no bounding has
 // been constructed Contains returns true.
 func (t *Tree) Contains(c Comparable) bool {
@@ -236,46 +439,52 @@ var inf = math.Inf(1)
 
 // Nearest returns the nearest value to the query and the distance between them.
 func (t *Tree) Nearest(q Comparable) (Comparable, float64) {
+	return t.NearestMetric(q, Euclidean{})
+}
+
+// NearestMetric returns the nearest value to the query and the distance between them
+// according to the metric m.
+func (t *Tree) NearestMetric(q Comparable, m Metric) (Comparable, float64) {
 	if t.Root == nil {
 		return nil, inf
 	}
-	n, dist := t.Root.search(q, inf)
+	n, dist := t.Root.search(q, m, inf)
 	if n == nil {
 		return nil, inf
 	}
 	return n.Point, dist
 }
 
-func (n *Node) search(q Comparable, dist float64) (*Node, float64) {
+func (n *Node) search(q Comparable, m Metric, dist float64) (*Node, float64) {
 	if n == nil {
 		return nil, inf
 	}
 
 	c := q.Compare(n.Point, n.Plane)
-	dist = math.Min(dist, q.Distance(n.Point))
+	dist = math.Min(dist, m.Distance(q, n.Point))
 
 	bn := n
 	if c <= 0 {
-		ln, ld := n.Left.search(q, dist)
+		ln, ld := n.Left.search(q, m, dist)
 		if ld < dist {
 			dist = ld
 			bn = ln
 		}
-		if c*c < dist {
-			rn, rd := n.Right.search(q, dist)
+		if m.PlaneDistance(c, n.Plane) < dist {
+			rn, rd := n.Right.search(q, m, dist)
 			if rd < dist {
 				bn, dist = rn, rd
 			}
 		}
 		return bn, dist
 	}
-	rn, rd := n.Right.search(q, dist)
+	rn, rd := n.Right.search(q, m, dist)
 	if rd < dist {
 		dist = rd
 		bn = rn
 	}
-	if c*c < dist {
-		ln, ld := n.Left.search(q, dist)
+	if m.PlaneDistance(c, n.Plane) < dist {
+		ln, ld := n.Left.search(q, m, dist)
 		if ld < dist {
 			bn, dist = ln, ld
 		}
@@ -355,11 +564,22 @@ type Keeper interface {
 // when Max() is called, and retains the results of the search in min sorted order after
 // the call to NearestSet returns.
 func (t *Tree) NearestSet(k Keeper, q Comparable) {
+	t.NearestSetMetric(k, q, Euclidean{})
+}
+
+// NearestSetMetric finds the nearest values to the query accepted by the provided Keeper,
+// k, according to the metric m. The distances kept by k are calculated by m. The behaviour
+// of k is as described for NearestSet.
+func (t *Tree) NearestSetMetric(k Keeper, q Comparable, m Metric) {
 	if t.Root == nil {
 		return
 	}
-	t.Root.searchSet(q, k)
+	t.Root.searchSet(q, m, k)
+	finish(k)
+}
 
+// finish sorts the values retained by k and removes any retained sentinel.
+func finish(k Keeper) {
 	// Check whether we have retained a sentinel
 	// and flag removal if we have.
 	removeSentinel := k.Len() != 0 && k.Max().Comparable == nil
@@ -375,23 +595,23 @@ func (t *Tree) NearestSet(k Keeper, q Comparable) {
 	}
 }
 
-func (n *Node) searchSet(q Comparable, k Keeper) {
+func (n *Node) searchSet(q Comparable, m Metric, k Keeper) {
 	if n == nil {
 		return
 	}
 
 	c := q.Compare(n.Point, n.Plane)
-	k.Keep(ComparableDist{Comparable: n.Point, Dist: q.Distance(n.Point)})
+	k.Keep(ComparableDist{Comparable: n.Point, Dist: m.Distance(q, n.Point)})
 	if c <= 0 {
-		n.Left.searchSet(q, k)
-		if c*c <= k.Max().Dist {
-			n.Right.searchSet(q, k)
+		n.Left.searchSet(q, m, k)
+		if m.PlaneDistance(c, n.Plane) <= k.Max().Dist {
+			n.Right.searchSet(q, m, k)
 		}
 		return
 	}
-	n.Right.searchSet(q, k)
-	if c*c <= k.Max().Dist {
-		n.Left.searchSet(q, k)
+	n.Right.searchSet(q, m, k)
+	if m.PlaneDistance(c, n.Plane) <= k.Max().Dist {
+		n.Left.searchSet(q, m, k)
 	}
 	return
 }
@@ -7,6 +7,7 @@ package kdtree
 import (
 	"flag"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
 	"reflect"
@@ -121,6 +122,112 @@ func (s *S) TestInsert(c *check.C) {
 	}
 }
 
+func (t *Tree) points() Points {
+	var p Points
+	t.Do(func(c Comparable, _ *Bounding, _ int) (done bool) {
+		p = append(p, c.(Point))
+		return
+	})
+	return p
+}
+
+func sortPoints(p Points) Points {
+	sort.Slice(p, func(i, j int) bool {
+		for d := range p[i] {
+			if p[i][d] != p[j][d] {
+				return p[i][d] < p[j][d]
+			}
+		}
+		return false
+	})
+	return p
+}
+
+func (s *S) TestDelete(c *check.C) {
+	t := New(append(Points(nil), wpData...), true)
+	c.Check(t.Delete(Point{3, 3}), check.Equals, false)
+	c.Check(t.Len(), check.Equals, wpData.Len())
+	for i, p := range []Point{{2, 3}, {7, 2}, {4, 7}, {9, 6}, {5, 4}, {8, 1}} {
+		c.Check(t.Delete(p), check.Equals, true, check.Commentf("Test %d: %v", i, p))
+		c.Check(t.Delete(p), check.Equals, false)
+		c.Check(t.Len(), check.Equals, wpData.Len()-i-1)
+		c.Check(t.Root.isKDTree(), check.Equals, true)
+		rest := t.points()
+		c.Check(len(rest), check.Equals, t.Len())
+		for _, r := range rest {
+			c.Check(r, check.Not(check.DeepEquals), p)
+		}
+		if t.Root != nil {
+			c.Check(t.Root.Bounding, check.DeepEquals, rest.Bounds(), check.Commentf("Test %d: %v", i, p))
+		}
+	}
+	c.Check(t.Root, check.IsNil)
+
+	nt := New(append(nbPoints(nil), nbWpData...), true)
+	c.Check(nt.Delete(nbPoint{5, 4}), check.Equals, true)
+	c.Check(nt.Root.isKDTree(), check.Equals, true)
+	c.Check(nt.Len(), check.Equals, nbWpData.Len()-1)
+	c.Check(nt.Root.Bounding, check.IsNil)
+}
+
+func (s *S) TestDeleteRandom(c *check.C) {
+	const n = 1000
+	data := make(Points, n)
+	for i := range data {
+		// Use a small range to ensure coincident coordinates.
+		data[i] = Point{float64(rand.Intn(20)), float64(rand.Intn(20)), rand.Float64()}
+	}
+	t := New(append(Points(nil), data...), true)
+	for i, p := range data[:n/2] {
+		c.Assert(t.Delete(p), check.Equals, true, check.Commentf("Test %d: %v", i, p))
+	}
+	c.Check(t.Len(), check.Equals, n/2)
+	c.Check(t.Root.isSplit(), check.Equals, true)
+	rest := data[n/2:]
+	c.Check(sortPoints(t.points()), check.DeepEquals, sortPoints(append(Points(nil), rest...)))
+	c.Check(t.Root.Bounding, check.DeepEquals, rest.Bounds())
+	for i := 0; i < 100; i++ {
+		q := Point{rand.Float64() * 20, rand.Float64() * 20, rand.Float64()}
+		_, d := t.Nearest(q)
+		_, ed := nearest(q, rest)
+		c.Check(d, check.Equals, ed)
+	}
+}
+
+// isSplit returns whether the points in the left and right subtrees of each node are
+// not greater and not less than the node's point in its plane, and whether the size and
+// bounding volume of each node describe its subtree. Unlike isKDTree,
+// isSplit allows coincident coordinates in both subtrees.
+func (n *Node) isSplit() bool {
+	if n == nil {
+		return true
+	}
+	var sub Points
+	ok := true
+	var walk func(m *Node, side float64)
+	walk = func(m *Node, side float64) {
+		if m == nil {
+			return
+		}
+		sub = append(sub, m.Point.(Point))
+		if side*m.Point.Compare(n.Point, n.Plane) < 0 {
+			ok = false
+		}
+		walk(m.Left, side)
+		walk(m.Right, side)
+	}
+	sub = append(sub, n.Point.(Point))
+	walk(n.Left, -1)
+	walk(n.Right, 1)
+	if n.Size != len(sub) {
+		return false
+	}
+	if n.Bounding != nil && !reflect.DeepEqual(n.Bounding, sub.Bounds()) {
+		return false
+	}
+	return ok && n.Left.isSplit() && n.Right.isSplit()
+}
+
 type compFn func(float64) bool
 
 func left(v float64) bool  { return v <= 0 }
@@ -367,6 +474,102 @@ func (s *S) TestNearestSetDist(c *check.C) {
 	}
 }
 
+func (s *S) TestStats(c *check.C) {
+	c.Check((&Tree{}).Stats(), check.DeepEquals, Stats{})
+
+	t := New(wpData, false)
+	st := t.Stats()
+	c.Check(st.Nodes, check.Equals, wpData.Len())
+	c.Check(st.Height, check.Equals, 3)
+	c.Check(st.Leaves, check.Equals, 3)
+	c.Check(st.Splits, check.DeepEquals, []int{4, 2})
+	c.Check(st.Imbalance, check.Equals, 1.0)
+	c.Check(st.Bytes, check.Equals, st.Nodes*int(unsafe.Sizeof(Node{})))
+
+	t = &Tree{}
+	for i := 0; i < 15; i++ {
+		t.Insert(Point{float64(i), float64(i)}, false)
+	}
+	st = t.Stats()
+	c.Check(st.Height, check.Equals, 15)
+	c.Check(st.Leaves, check.Equals, 1)
+	c.Check(st.MeanDepth, check.Equals, 7.0)
+	c.Check(st.Imbalance, check.Equals, 15.0/4)
+}
+
+func (s *S) TestRebalance(c *check.C) {
+	var (
+		t    = &Tree{}
+		data Points
+	)
+	for i := 0; i < 15; i++ {
+		p := Point{float64(i), float64(i)}
+		data = append(data, p)
+		t.Insert(p, true)
+	}
+	c.Check(t.Stats().Height, check.Equals, 15)
+	t.Rebalance()
+	c.Check(t.Len(), check.Equals, 15)
+	c.Check(t.Stats().Height, check.Equals, 4)
+	c.Check(t.Root.isSplit(), check.Equals, true)
+	c.Check(t.Root.Bounding, check.DeepEquals, data.Bounds())
+	c.Check(sortPoints(t.points()), check.DeepEquals, data)
+
+	(&Tree{}).Rebalance()
+
+	// Rebuilt subtrees do not depend on a source of randomness,
+	// so seeded trees remain reproducible after rebuilding.
+	base, extra := make(Points, 500), make(Points, 500)
+	for i := range base {
+		base[i] = Point{rand.Float64(), rand.Float64()}
+		extra[i] = Point{rand.Float64(), rand.Float64()}
+	}
+	seeded := func() *Tree {
+		t := New(RandPoints{Points: append(Points(nil), base...), Rand: rand.New(rand.NewSource(1))}, true)
+		t.Alpha = 0.6
+		for _, p := range extra {
+			t.Insert(p, true)
+		}
+		return t
+	}
+	a, b := seeded(), seeded()
+	c.Check(reflect.DeepEqual(a, b), check.Equals, true)
+	a.Rebalance()
+	b.Rebalance()
+	c.Check(reflect.DeepEqual(a, b), check.Equals, true)
+}
+
+func (s *S) TestInsertAlpha(c *check.C) {
+	const (
+		n     = 1000
+		alpha = 0.7
+	)
+	for _, bounding := range []bool{false, true} {
+		var (
+			t    = &Tree{Alpha: alpha}
+			data Points
+		)
+		for i := 0; i < n; i++ {
+			// Streaming sorted values is the worst case without rebalancing.
+			p := Point{float64(i), rand.Float64(), rand.Float64()}
+			data = append(data, p)
+			t.Insert(p, bounding)
+			c.Assert(t.Stats().Height-1 <= int(math.Log(float64(i+1))/math.Log(1/alpha)), check.Equals, true,
+				check.Commentf("Insert %d", i))
+		}
+		c.Check(t.Len(), check.Equals, n)
+		c.Check(t.Root.isSplit(), check.Equals, true)
+		c.Check(t.Root.Bounding != nil, check.Equals, bounding)
+		c.Check(sortPoints(t.points()), check.DeepEquals, data)
+		for i := 0; i < 100; i++ {
+			q := Point{rand.Float64() * n, rand.Float64(), rand.Float64()}
+			_, d := t.Nearest(q)
+			_, ed := nearest(q, data)
+			c.Check(d, check.Equals, ed)
+		}
+	}
+}
+
 func (s *S) TestDo(c *check.C) {
 	var result Points
 	t := New(wpData, false)
@@ -38,6 +38,12 @@ type SortSlicer interface {
 // placed placed before k in the resulting list and all elements greater than it are placed
 // after the position k.
 func Select(list SortSlicer, k int) int {
+	return SelectRand(list, k, nil)
+}
+
+// SelectRand performs a Select using rnd as the source of random pivots. If rnd is nil,
+// the global source from math/rand is used.
+func SelectRand(list SortSlicer, k int, rnd *rand.Rand) int {
 	var (
 		start int
 		end   = list.Len()
@@ -57,7 +63,7 @@ func Select(list SortSlicer, k int) int {
 			panic("kdtree: internal inconsistency")
 		}
 		sub := list.Slice(start, end)
-		pivot := Partition(sub, rand.Intn(sub.Len()))
+		pivot := Partition(sub, intn(rnd, sub.Len()))
 		switch {
 		case pivot == k:
 			return k
@@ -92,13 +98,27 @@ func MedianOfMedians(list SortSlicer) int {
 
 // MedianOfRandoms returns the index to the median value of up to n randomly chosen elements in list.
 func MedianOfRandoms(list SortSlicer, n int) int {
+	return MedianOfRandomsRand(list, n, nil)
+}
+
+// MedianOfRandomsRand performs a MedianOfRandoms using rnd as the source of random values.
+// If rnd is nil, the global source from math/rand is used.
+func MedianOfRandomsRand(list SortSlicer, n int, rnd *rand.Rand) int {
 	if l := list.Len(); n <= l {
 		for i := 0; i < n; i++ {
-			list.Swap(i, rand.Intn(n))
+			list.Swap(i, intn(rnd, n))
 		}
 	} else {
 		n = l
 	}
-	Select(list.Slice(0, n), n/2)
+	SelectRand(list.Slice(0, n), n/2, rnd)
 	return n / 2
 }
+
+// intn returns a random int in [0, n) from rnd, or from the global source if rnd is nil.
+func intn(rnd *rand.Rand, n int) int {
+	if rnd == nil {
+		return rand.Intn(n)
+	}
+	return rnd.Intn(n)
+}
@@ -6,6 +6,7 @@ package kdtree
 
 import (
 	"math/rand"
+	"reflect"
 	"sort"
 	"testing"
 
@@ -68,6 +69,29 @@ func (s *S) TestSelect(c *check.C) {
 	}
 }
 
+func (s *S) TestSelectRand(c *check.C) {
+	list := make(Ints, 1000)
+	for i := range list {
+		list[i] = rand.Intn(100)
+	}
+	var results []Ints
+	for i := 0; i < 2; i++ {
+		l := append(Ints(nil), list...)
+		SelectRand(l, 500, rand.New(rand.NewSource(1)))
+		c.Check(l[500], check.Equals, sortSelection(append(Ints(nil), list...), 500))
+		results = append(results, l)
+	}
+	c.Check(results[0], check.DeepEquals, results[1])
+
+	results = results[:0]
+	for i := 0; i < 2; i++ {
+		l := append(Ints(nil), list...)
+		MedianOfRandomsRand(l, Randoms, rand.New(rand.NewSource(1)))
+		results = append(results, l)
+	}
+	c.Check(results[0], check.DeepEquals, results[1])
+}
+
 func (s *S) TestMedianOfMedians(c *check.C) {
 	list := make(Ints, 1e4)
 	for i := range list {
@@ -165,3 +189,25 @@ func BenchmarkSM(b *testing.B) {
 		sort.Sort(list)
 	}
 }
+
+func (s *S) TestRandPoints(c *check.C) {
+	data := make(Points, 1000)
+	for i := range data {
+		data[i] = Point{rand.Float64(), rand.Float64(), rand.Float64()}
+	}
+	seeded := func(seed int64, exact bool) *Tree {
+		return New(RandPoints{
+			Points: append(Points(nil), data...),
+			Rand:   rand.New(rand.NewSource(seed)),
+			Exact:  exact,
+		}, true)
+	}
+	c.Check(reflect.DeepEqual(seeded(1, false), seeded(1, false)), check.Equals, true)
+	c.Check(reflect.DeepEqual(seeded(1, false), seeded(2, false)), check.Equals, false)
+	c.Check(seeded(1, false).Root.isKDTree(), check.Equals, true)
+
+	exact := seeded(1, true)
+	c.Check(exact.Root.isKDTree(), check.Equals, true)
+	c.Check(exact.Stats().Imbalance, check.Equals, 1.0)
+	c.Check(reflect.DeepEqual(exact, seeded(2, true)), check.Equals, true)
+}
@@ -0,0 +1,119 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import "math"
+
+var (
+	_ Metric = Euclidean{}
+	_ Metric = Manhattan{}
+	_ Metric = Chebyshev{}
+	_ Metric = Minkowski{}
+)
+
+// A Metric defines the distance between Comparables used to guide nearest neighbour
+// searches. Distances returned by a Metric may be any monotonic transformation of the
+// true distance, such as the square of the Euclidean distance, provided Distance and
+// PlaneDistance are consistent.
+type Metric interface {
+	// Distance returns the distance between a and b.
+	Distance(a, b Comparable) float64
+
+	// PlaneDistance returns the distance between a point and the
+	// plane with normal vector along dimension d, where c is the
+	// value returned by the point's Compare method with a point
+	// on the plane. PlaneDistance must not be greater than the
+	// Distance between the point and any point on the far side
+	// of the plane.
+	PlaneDistance(c float64, d Dim) float64
+}
+
+// Euclidean is the squared Euclidean distance Metric. Distances are calculated by the
+// Distance method of the Comparable.
+type Euclidean struct{}
+
+// Distance returns the squared Euclidean distance between a and b.
+func (Euclidean) Distance(a, b Comparable) float64 { return a.Distance(b) }
+
+// PlaneDistance returns the squared Euclidean distance to a plane.
+func (Euclidean) PlaneDistance(c float64, _ Dim) float64 { return c * c }
+
+// Manhattan is the L1 distance Metric.
+type Manhattan struct{}
+
+// Distance returns the Manhattan distance between a and b.
+func (Manhattan) Distance(a, b Comparable) float64 {
+	var sum float64
+	for d := Dim(0); d < Dim(a.Dims()); d++ {
+		sum += math.Abs(a.Compare(b, d))
+	}
+	return sum
+}
+
+// PlaneDistance returns the Manhattan distance to a plane.
+func (Manhattan) PlaneDistance(c float64, _ Dim) float64 { return math.Abs(c) }
+
+// Chebyshev is the L∞ distance Metric.
+type Chebyshev struct{}
+
+// Distance returns the Chebyshev distance between a and b.
+func (Chebyshev) Distance(a, b Comparable) float64 {
+	var max float64
+	for d := Dim(0); d < Dim(a.Dims()); d++ {
+		max = math.Max(max, math.Abs(a.Compare(b, d)))
+	}
+	return max
+}
+
+// PlaneDistance returns the Chebyshev distance to a plane.
+func (Chebyshev) PlaneDistance(c float64, _ Dim) float64 { return math.Abs(c) }
+
+// Minkowski is the weighted Lp distance Metric. To avoid calculating roots, the distance
+// is returned as the pth power of the Lp distance, except when P is +Inf where the weighted
+// Chebyshev distance is returned. P must not be less than 1 and the weights must not be
+// negative.
+type Minkowski struct {
+	P float64
+
+	// W holds the weight of each dimension.
+	// If W is nil, all weights are 1.
+	W []float64
+}
+
+func (m Minkowski) weight(d Dim) float64 {
+	if m.W == nil {
+		return 1
+	}
+	return m.W[d]
+}
+
+// Distance returns the pth power of the weighted Lp distance between a and b.
+func (m Minkowski) Distance(a, b Comparable) float64 {
+	var dist float64
+	for d := Dim(0); d < Dim(a.Dims()); d++ {
+		v := m.PlaneDistance(a.Compare(b, d), d)
+		if math.IsInf(m.P, 1) {
+			dist = math.Max(dist, v)
+		} else {
+			dist += v
+		}
+	}
+	return dist
+}
+
+// PlaneDistance returns the pth power of the weighted Lp distance to a plane.
+func (m Minkowski) PlaneDistance(c float64, d Dim) float64 {
+	c = math.Abs(c)
+	switch m.P {
+	case 1:
+	case 2:
+		c *= c
+	default:
+		if !math.IsInf(m.P, 1) {
+			c = math.Pow(c, m.P)
+		}
+	}
+	return m.weight(d) * c
+}
@@ -0,0 +1,82 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"gopkg.in/check.v1"
+)
+
+var metrics = []Metric{
+	Euclidean{},
+	Manhattan{},
+	Chebyshev{},
+	Minkowski{P: 1},
+	Minkowski{P: 2, W: []float64{1, 4, 0.25}},
+	Minkowski{P: 3},
+	Minkowski{P: math.Inf(1), W: []float64{2, 1, 1}},
+}
+
+func (s *S) TestMetric(c *check.C) {
+	a, b := Point{0, 0, 0}, Point{1, -2, 3}
+	for _, test := range []struct {
+		m    Metric
+		dist float64
+	}{
+		{m: Euclidean{}, dist: 14},
+		{m: Manhattan{}, dist: 6},
+		{m: Chebyshev{}, dist: 3},
+		{m: Minkowski{P: 1}, dist: 6},
+		{m: Minkowski{P: 2, W: []float64{1, 4, 0.25}}, dist: 1 + 16 + 2.25},
+		{m: Minkowski{P: 3}, dist: 36},
+		{m: Minkowski{P: math.Inf(1), W: []float64{4, 1, 1}}, dist: 4},
+	} {
+		c.Check(test.m.Distance(a, b), check.Equals, test.dist, check.Commentf("%#v", test.m))
+		c.Check(test.m.Distance(b, a), check.Equals, test.dist, check.Commentf("%#v", test.m))
+	}
+}
+
+func (s *S) TestNearestMetric(c *check.C) {
+	const n = 1000
+	data := make(Points, n)
+	for i := range data {
+		data[i] = Point{rand.Float64(), rand.Float64(), rand.Float64()}
+	}
+	t := New(append(Points(nil), data...), false)
+	for _, m := range metrics {
+		for i := 0; i < 100; i++ {
+			q := Point{rand.Float64(), rand.Float64(), rand.Float64()}
+			dists := make([]float64, n)
+			for j, p := range data {
+				dists[j] = m.Distance(q, p)
+			}
+			sort.Float64s(dists)
+
+			_, d := t.NearestMetric(q, m)
+			c.Check(d, check.Equals, dists[0], check.Commentf("%#v", m))
+
+			keep := NewNKeeper(10)
+			t.NearestSetMetric(keep, q, m)
+			c.Assert(keep.Len(), check.Equals, 10)
+			for j, e := range keep.Heap {
+				c.Check(e.Dist, check.Equals, dists[j], check.Commentf("%#v", m))
+			}
+
+			r := dists[20]
+			dk := NewDistKeeper(r)
+			t.NearestSetMetric(dk, q, m)
+			var want int
+			for _, d := range dists {
+				if d <= r {
+					want++
+				}
+			}
+			c.Check(dk.Len(), check.Equals, want, check.Commentf("%#v", m))
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// parallelCutoff is the size of the smallest list that is split
+// between goroutines by NewParallel.
+var parallelCutoff = 1 << 12
+
+// NewParallel returns a k-d tree constructed from the values in p using up to workers
+// goroutines. If p is a Bounder and bounding is true, bounds are determined for each node.
+// Subtrees are constructed concurrently, so the Pivot, Slice and Bounds methods of p must
+// be safe to call concurrently on non-overlapping slices of p. If the Pivot method of p is
+// deterministic, the returned tree is identical to the tree returned by New.
+//
+// If p is a Reseeder, each subtree that may be built by a separate goroutine is given its
+// own source of randomness seeded from the global source in math/rand, as described for
+// NewParallelSeed, so p does not need to be safe for concurrent use. NewParallelSeed
+// should be used when the tree must be reproducible.
+func NewParallel(p Interface, bounding bool, workers int) *Tree {
+	if workers < 2 {
+		return New(p, bounding)
+	}
+	if r, ok := p.(Reseeder); ok {
+		return NewParallelSeed(r, bounding, workers, rand.Int63())
+	}
+	_, ok := p.(bounder)
+	return &Tree{
+		Root:  buildParallel(p, 0, ok && bounding, nil, make(chan struct{}, workers-1)),
+		Count: p.Len(),
+	}
+}
+
+// A Reseeder is an Interface that is able to replace its source of randomness for pivot
+// selection.
+type Reseeder interface {
+	Interface
+
+	// Reseed returns the list using rnd as its
+	// source of randomness for pivot selection.
+	Reseed(rnd *rand.Rand) Interface
+}
+
+// NewParallelSeed returns a k-d tree constructed from the values in p using up to workers
+// goroutines as described for NewParallel. Pivots are selected using sources of randomness
+// derived from seed: each subtree that may be built by a separate goroutine is given its
+// own source, seeded from the source of its parent before the subtree is built. The
+// returned tree depends only on p and seed, and not on the number of workers.
+func NewParallelSeed(p Reseeder, bounding bool, workers int, seed int64) *Tree {
+	if workers < 1 {
+		workers = 1
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	_, ok := p.(bounder)
+	return &Tree{
+		Root:  buildParallel(p.Reseed(rnd), 0, ok && bounding, rnd, make(chan struct{}, workers-1)),
+		Count: p.Len(),
+	}
+}
+
+// buildParallel builds a subtree from p, starting a new goroutine to build the left
+// subtree if a token can be placed in sem. If rnd is not nil, it is the source of
+// randomness held by p, and p is a Reseeder.
+func buildParallel(p Interface, plane Dim, bounding bool, rnd *rand.Rand, sem chan struct{}) *Node {
+	if p.Len() < parallelCutoff {
+		if bounding {
+			return buildBounded(p.(bounder), plane, bounding)
+		}
+		return build(p, plane)
+	}
+
+	piv := p.Pivot(plane)
+	d := p.Index(piv)
+	np := (plane + 1) % Dim(d.Dims())
+
+	n := &Node{
+		Point: d,
+		Plane: plane,
+		Size:  p.Len(),
+	}
+	if bounding {
+		n.Bounding = p.(bounder).Bounds()
+	}
+	left, right := p.Slice(0, piv), p.Slice(piv+1, p.Len())
+	var lrnd, rrnd *rand.Rand
+	if rnd != nil {
+		// Seed the sources of both subtrees whether or not
+		// they are built concurrently so that the tree does
+		// not depend on goroutine scheduling.
+		lrnd = rand.New(rand.NewSource(rnd.Int63()))
+		rrnd = rand.New(rand.NewSource(rnd.Int63()))
+		left = left.(Reseeder).Reseed(lrnd)
+		right = right.(Reseeder).Reseed(rrnd)
+	}
+	select {
+	case sem <- struct{}{}:
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			n.Left = buildParallel(left, np, bounding, lrnd, sem)
+		}()
+		n.Right = buildParallel(right, np, bounding, rrnd, sem)
+		wg.Wait()
+	default:
+		n.Left = buildParallel(left, np, bounding, lrnd, sem)
+		n.Right = buildParallel(right, np, bounding, rrnd, sem)
+	}
+	return n
+}
@@ -0,0 +1,137 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+// sortedPoints is a Points with a deterministic Pivot.
+type sortedPoints Points
+
+func (p sortedPoints) Bounds() *Bounding              { return Points(p).Bounds() }
+func (p sortedPoints) Index(i int) Comparable         { return p[i] }
+func (p sortedPoints) Len() int                       { return len(p) }
+func (p sortedPoints) Slice(start, end int) Interface { return p[start:end] }
+func (p sortedPoints) Pivot(d Dim) int {
+	sort.Sort(Plane{Points: Points(p), Dim: d})
+	return len(p) / 2
+}
+
+// seededPoints is a Points with a Pivot that depends on its source of randomness.
+type seededPoints struct {
+	Points
+	rnd *rand.Rand
+}
+
+func (p seededPoints) Pivot(d Dim) int {
+	return Partition(Plane{Points: p.Points, Dim: d}, p.rnd.Intn(len(p.Points)))
+}
+func (p seededPoints) Slice(start, end int) Interface {
+	p.Points = p.Points[start:end]
+	return p
+}
+func (p seededPoints) Reseed(rnd *rand.Rand) Interface { p.rnd = rnd; return p }
+
+func (s *S) TestNewParallel(c *check.C) {
+	defer func(n int) { parallelCutoff = n }(parallelCutoff)
+	parallelCutoff = 16
+
+	data := make(sortedPoints, 1e4)
+	for i := range data {
+		data[i] = Point{rand.Float64(), rand.Float64(), float64(rand.Intn(10))}
+	}
+	for _, bounding := range []bool{false, true} {
+		for _, workers := range []int{0, 1, 2, 8} {
+			want := New(append(sortedPoints(nil), data...), bounding)
+			got := NewParallel(append(sortedPoints(nil), data...), bounding, workers)
+			c.Check(got.Len(), check.Equals, want.Len())
+			c.Check(reflect.DeepEqual(got, want), check.Equals, true,
+				check.Commentf("bounding=%t workers=%d", bounding, workers))
+		}
+	}
+
+	// Trees built with a randomised Pivot are not identical
+	// to those built by New, but must be valid.
+	p := make(Points, 1e4)
+	for i := range p {
+		p[i] = Point{rand.Float64(), rand.Float64(), rand.Float64()}
+	}
+	t := NewParallel(append(Points(nil), p...), true, 4)
+	c.Check(t.Len(), check.Equals, len(p))
+	c.Check(t.Root.isSplit(), check.Equals, true)
+	c.Check(sortPoints(t.points()), check.DeepEquals, sortPoints(p))
+	c.Check(NewParallel(Points(nil), true, 4).Root, check.IsNil)
+}
+
+func (s *S) TestNewParallelSeed(c *check.C) {
+	defer func(n int) { parallelCutoff = n }(parallelCutoff)
+	parallelCutoff = 16
+
+	data := make(Points, 5000)
+	for i := range data {
+		data[i] = Point{rand.Float64(), rand.Float64(), rand.Float64()}
+	}
+	seeded := func(seed int64, workers int) *Tree {
+		return NewParallelSeed(seededPoints{Points: append(Points(nil), data...)}, true, workers, seed)
+	}
+	want := seeded(1, 1)
+	c.Check(want.Len(), check.Equals, len(data))
+	c.Check(want.Root.isKDTree(), check.Equals, true)
+	c.Check(sortPoints(want.points()), check.DeepEquals, sortPoints(append(Points(nil), data...)))
+	for _, workers := range []int{0, 2, 8} {
+		c.Check(reflect.DeepEqual(seeded(1, workers), want), check.Equals, true,
+			check.Commentf("workers=%d", workers))
+	}
+	c.Check(reflect.DeepEqual(seeded(2, 8), want), check.Equals, false)
+
+	// A Reseeder holding a source that is not safe for concurrent
+	// use is given a source for each concurrently built subtree.
+	t := NewParallel(seededPoints{Points: append(Points(nil), data...), rnd: rand.New(rand.NewSource(1))}, true, 8)
+	c.Check(t.Root.isKDTree(), check.Equals, true)
+	c.Check(sortPoints(t.points()), check.DeepEquals, sortPoints(append(Points(nil), data...)))
+}
+
+func (s *S) TestNewParallelExact(c *check.C) {
+	defer func(n int) { parallelCutoff = n }(parallelCutoff)
+	parallelCutoff = 16
+
+	// Exact medians do not depend on the source of randomness, so
+	// concurrently built trees are identical to those built by New.
+	data := make(Points, 1000)
+	for i := range data {
+		data[i] = Point{rand.Float64(), rand.Float64(), rand.Float64()}
+	}
+	want := New(RandPoints{Points: append(Points(nil), data...), Exact: true}, true)
+	got := NewParallel(RandPoints{Points: append(Points(nil), data...), Exact: true}, true, 4)
+	c.Check(reflect.DeepEqual(got, want), check.Equals, true)
+}
+
+func BenchmarkNewParallel(b *testing.B) {
+	data := make(Points, 1e6)
+	for i := range data {
+		data[i] = Point{rand.Float64(), rand.Float64(), rand.Float64()}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewParallel(data, false, 8)
+	}
+}
+
+func BenchmarkNewSerial(b *testing.B) {
+	data := make(Points, 1e6)
+	for i := range data {
+		data[i] = Point{rand.Float64(), rand.Float64(), rand.Float64()}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New(data, false)
+	}
+}
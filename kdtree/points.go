@@ -6,10 +6,12 @@ package kdtree
 
 import (
 	"math"
+	"math/rand"
 )
 
 var (
 	_ Interface  = Points{}
+	_ Reseeder   = RandPoints{}
 	_ Comparable = Point{}
 )
 
@@ -67,14 +69,49 @@ func (p Points) Len() int                       { return len(p) }
 func (p Points) Pivot(d Dim) int                { return Plane{Points: p, Dim: d}.Pivot() }
 func (p Points) Slice(start, end int) Interface { return p[start:end] }
 
+// RandPoints is a collection of point values that satisfies the Interface, allowing
+// the source of randomness used for pivot selection to be specified.
+type RandPoints struct {
+	Points
+
+	// Rand is the source of randomness used to select pivots.
+	// If Rand is nil, the global source from math/rand is used.
+	// NewParallel and NewParallelSeed give each concurrently
+	// built subtree its own source.
+	Rand *rand.Rand
+
+	// Exact specifies that pivots are the exact median of the
+	// points in the dimension. When the points have distinct
+	// values in each dimension, trees built with exact medians
+	// do not depend on the source of randomness.
+	Exact bool
+}
+
+func (p RandPoints) Pivot(d Dim) int {
+	return Plane{Points: p.Points, Dim: d, Rand: p.Rand, Exact: p.Exact}.Pivot()
+}
+func (p RandPoints) Slice(start, end int) Interface  { p.Points = p.Points[start:end]; return p }
+func (p RandPoints) Reseed(rnd *rand.Rand) Interface { p.Rand = rnd; return p }
+
 // A Plane is a wrapping type that allows a Points type be pivoted on a dimension.
 type Plane struct {
 	Dim
 	Points
+
+	// Rand and Exact specify pivot selection
+	// as described for RandPoints.
+	Rand  *rand.Rand
+	Exact bool
 }
 
-func (p Plane) Less(i, j int) bool              { return p.Points[i][p.Dim] < p.Points[j][p.Dim] }
-func (p Plane) Pivot() int                      { return Partition(p, MedianOfRandoms(p, Randoms)) }
+func (p Plane) Less(i, j int) bool { return p.Points[i][p.Dim] < p.Points[j][p.Dim] }
+func (p Plane) Pivot() int {
+	if p.Exact {
+		SelectRand(p, p.Len()/2, p.Rand)
+		return p.Len() / 2
+	}
+	return Partition(p, MedianOfRandomsRand(p, Randoms, p.Rand))
+}
 func (p Plane) Slice(start, end int) SortSlicer { p.Points = p.Points[start:end]; return p }
 func (p Plane) Swap(i, j int) {
 	p.Points[i], p.Points[j] = p.Points[j], p.Points[i]
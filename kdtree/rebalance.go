@@ -0,0 +1,116 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"math"
+	"sort"
+)
+
+// Rebalance rebuilds the tree so that it is balanced. Bounding volumes are reconstructed
+// if the tree has bounding volumes and the stored points are Extenders.
+func (t *Tree) Rebalance() {
+	t.Root = t.Root.rebuild()
+}
+
+// rebalanceAfter rebuilds the scapegoat subtree on the insertion path of c if the
+// depth of the inserted node exceeds the depth allowed by t.Alpha.
+//
+// The approach is described in 'Scapegoat trees.' I. Galperin and R. L. Rivest
+// Proceedings of the fourth annual ACM-SIAM Symposium on Discrete algorithms 165–174.
+func (t *Tree) rebalanceAfter(c Comparable) {
+	// Insertion is deterministic, so the inserted node is the
+	// leaf at the end of the insertion path of c.
+	var path []*Node
+	for n := t.Root; n != nil; {
+		path = append(path, n)
+		if c.Compare(n.Point, n.Plane) <= 0 {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	if float64(len(path)-1) <= math.Log(float64(t.Count))/math.Log(1/t.Alpha) {
+		return
+	}
+
+	for i := len(path) - 2; i >= 0; i-- {
+		n := path[i]
+		if float64(path[i+1].Size) > t.Alpha*float64(n.Size) {
+			r := n.rebuild()
+			switch {
+			case i == 0:
+				t.Root = r
+			case path[i-1].Left == n:
+				path[i-1].Left = r
+			default:
+				path[i-1].Right = r
+			}
+			return
+		}
+	}
+}
+
+// rebuild returns a balanced subtree holding the points in the subtree rooted at n.
+// The root of the returned subtree splits on the same plane as n. The returned subtree
+// depends only on the subtree rooted at n.
+func (n *Node) rebuild() *Node {
+	if n == nil {
+		return nil
+	}
+	p := make(comparables, 0, n.Size)
+	n.do(func(c Comparable, _ *Bounding, _ int) (done bool) {
+		p = append(p, c)
+		return
+	}, 0)
+	r := build(p, n.Plane)
+	if n.Bounding != nil {
+		r.boundAll()
+	}
+	return r
+}
+
+// boundAll sets the bounding volumes of all the nodes in the subtree rooted at n.
+func (n *Node) boundAll() {
+	if n == nil {
+		return
+	}
+	n.Left.boundAll()
+	n.Right.boundAll()
+	n.bound()
+}
+
+// comparables is an Interface used to rebuild subtrees from their stored points.
+type comparables []Comparable
+
+func (p comparables) Index(i int) Comparable         { return p[i] }
+func (p comparables) Len() int                       { return len(p) }
+func (p comparables) Pivot(d Dim) int                { return comparablePlane{comparables: p, Dim: d}.Pivot() }
+func (p comparables) Slice(start, end int) Interface { return p[start:end] }
+
+// comparablePlane is a wrapping type that allows a comparables be pivoted on a dimension.
+type comparablePlane struct {
+	Dim
+	comparables
+}
+
+func (p comparablePlane) Less(i, j int) bool {
+	return p.comparables[i].Compare(p.comparables[j], p.Dim) < 0
+}
+
+// Pivot returns the exact median of the list, found by sorting so that rebuilt
+// subtrees do not depend on a source of randomness. This keeps trees built from
+// seeded RandPoints reproducible after rebalancing.
+func (p comparablePlane) Pivot() int {
+	sort.Sort(p)
+	return p.Len() / 2
+}
+func (p comparablePlane) Slice(start, end int) SortSlicer {
+	p.comparables = p.comparables[start:end]
+	return p
+}
+func (p comparablePlane) Swap(i, j int) {
+	p.comparables[i], p.comparables[j] = p.comparables[j], p.comparables[i]
+}
@@ -0,0 +1,171 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+// RegionTree is an index of regions described by bounding volumes. Each region is held
+// in a k-d tree as a point in a space with twice the number of dimensions of the region,
+// with coordinates given by the minimum corner of the region followed by its maximum
+// corner. Queries for regions intersecting a volume are then range queries in the
+// corner space. The zero value of a RegionTree is an empty index.
+type RegionTree struct {
+	tree Tree
+}
+
+// regionAlpha is the weight balance factor used to rebuild
+// unbalanced subtrees of a RegionTree after an Insert.
+const regionAlpha = 0.75
+
+// NewRegionTree returns a RegionTree holding the regions in r. The Bounds method of each
+// region must return a non-nil volume, and the volumes of all regions must have the same
+// number of dimensions. Bounds are retained by the RegionTree when a region is added to it,
+// so a region must not change its bounds while it is held.
+func NewRegionTree(r []Bounder) *RegionTree {
+	p := make(comparables, len(r))
+	for i, v := range r {
+		p[i] = newRegionPoint(v)
+	}
+	return &RegionTree{tree: Tree{
+		Root:  build(p, 0),
+		Count: len(p),
+		Alpha: regionAlpha,
+	}}
+}
+
+// regionPoint is the corner space representation of a region.
+type regionPoint struct {
+	region Bounder
+	min    Comparable
+	max    Comparable
+}
+
+func newRegionPoint(r Bounder) regionPoint {
+	b := r.Bounds()
+	if b == nil {
+		panic("kdtree: nil region bounds")
+	}
+	return regionPoint{region: r, min: b[0], max: b[1]}
+}
+
+func (p regionPoint) Compare(c Comparable, d Dim) float64 {
+	q := c.(regionPoint)
+	if k := Dim(p.min.Dims()); d >= k {
+		return p.max.Compare(q.max, d-k)
+	}
+	return p.min.Compare(q.min, d)
+}
+func (p regionPoint) Dims() int { return 2 * p.min.Dims() }
+func (p regionPoint) Distance(c Comparable) float64 {
+	q := c.(regionPoint)
+	return p.min.Distance(q.min) + p.max.Distance(q.max)
+}
+
+// Len returns the number of regions in the index.
+func (t *RegionTree) Len() int { return t.tree.Count }
+
+// Insert adds the region r to the index. The requirements on r are as described for
+// NewRegionTree. Unbalanced subtrees are rebuilt after the insertion as described for
+// the Alpha field of Tree.
+func (t *RegionTree) Insert(r Bounder) {
+	t.tree.Alpha = regionAlpha
+	t.tree.Insert(newRegionPoint(r), false)
+}
+
+// Delete removes the region r from the index, returning whether it was found. Regions are
+// identified by equality, so r must be a comparable value. No rebalancing is performed.
+func (t *RegionTree) Delete(r Bounder) bool {
+	p := newRegionPoint(r)
+	target := t.tree.Root.findRegion(p)
+	if target == nil {
+		return false
+	}
+	t.tree.Root, _ = t.tree.Root.delete(p, target, false)
+	t.tree.Count--
+	return true
+}
+
+// findRegion returns the node in the subtree rooted at n holding the region of p.
+func (n *Node) findRegion(p regionPoint) *Node {
+	if n == nil {
+		return nil
+	}
+	if n.Point.(regionPoint).region == p.region {
+		return n
+	}
+	c := p.Compare(n.Point, n.Plane)
+	if c <= 0 {
+		if m := n.Left.findRegion(p); m != nil {
+			return m
+		}
+	}
+	if c >= 0 {
+		return n.Right.findRegion(p)
+	}
+	return nil
+}
+
+// A RegionOperation is a function that operates on a region. If done is returned true,
+// the RegionOperation is indicating that no further work needs to be done and so the
+// calling function should traverse no further.
+type RegionOperation func(Bounder) (done bool)
+
+// Do performs fn on all regions stored in the index. A boolean is returned indicating
+// whether the traversal was interrupted by a RegionOperation returning true.
+func (t *RegionTree) Do(fn RegionOperation) bool {
+	return t.tree.Do(func(c Comparable, _ *Bounding, _ int) (done bool) {
+		return fn(c.(regionPoint).region)
+	})
+}
+
+// DoIntersecting performs fn on all regions stored in the index that intersect b. Regions
+// that share only a boundary with b intersect it. A boolean is returned indicating whether
+// the traversal was interrupted by a RegionOperation returning true.
+func (t *RegionTree) DoIntersecting(fn RegionOperation, b *Bounding) bool {
+	return t.tree.Root.doIntersecting(fn, b)
+}
+
+// DoContaining performs fn on all regions stored in the index that contain p, including
+// regions with p on their boundary. A boolean is returned indicating whether the traversal
+// was interrupted by a RegionOperation returning true.
+func (t *RegionTree) DoContaining(fn RegionOperation, p Comparable) bool {
+	return t.tree.Root.doIntersecting(fn, &Bounding{p, p})
+}
+
+// doIntersecting performs fn on the regions in the subtree rooted at n with a minimum corner
+// no greater than b[1] and a maximum corner no less than b[0] in every dimension.
+func (n *Node) doIntersecting(fn RegionOperation, b *Bounding) (done bool) {
+	if n == nil {
+		return false
+	}
+	p := n.Point.(regionPoint)
+	k := Dim(p.min.Dims())
+
+	// Values in the left subtree are no greater than
+	// the value of n in the plane of n, and values
+	// in the right subtree are no less.
+	left, right := true, true
+	if n.Plane < k {
+		right = p.min.Compare(b[1], n.Plane) <= 0
+	} else {
+		left = p.max.Compare(b[0], n.Plane-k) >= 0
+	}
+
+	if left && n.Left.doIntersecting(fn, b) {
+		return true
+	}
+	if p.intersects(b) && fn(p.region) {
+		return true
+	}
+	return right && n.Right.doIntersecting(fn, b)
+}
+
+// intersects returns whether the region of p intersects b.
+func (p regionPoint) intersects(b *Bounding) bool {
+	for d := Dim(0); d < Dim(p.min.Dims()); d++ {
+		if p.min.Compare(b[1], d) > 0 || p.max.Compare(b[0], d) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kdtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+type box struct {
+	id int
+	b  Bounding
+}
+
+func (b *box) Bounds() *Bounding { return &b.b }
+
+func randBoxes(n int, size float64) []Bounder {
+	r := make([]Bounder, n)
+	for i := range r {
+		min := Point{rand.Float64(), rand.Float64()}
+		max := Point{min[0] + rand.Float64()*size, min[1] + rand.Float64()*size}
+		r[i] = &box{id: i, b: Bounding{min, max}}
+	}
+	return r
+}
+
+// regionIDs returns the sorted ids of the boxes in r that intersect b.
+func regionIDs(r []Bounder, b *Bounding) []int {
+	var ids []int
+	for _, v := range r {
+		if newRegionPoint(v).intersects(b) {
+			ids = append(ids, v.(*box).id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (t *RegionTree) intersectingIDs(b *Bounding) []int {
+	var ids []int
+	t.DoIntersecting(func(r Bounder) (done bool) {
+		ids = append(ids, r.(*box).id)
+		return
+	}, b)
+	sort.Ints(ids)
+	return ids
+}
+
+func (t *RegionTree) containingIDs(p Point) []int {
+	var ids []int
+	t.DoContaining(func(r Bounder) (done bool) {
+		ids = append(ids, r.(*box).id)
+		return
+	}, p)
+	sort.Ints(ids)
+	return ids
+}
+
+func (s *S) checkRegions(c *check.C, t *RegionTree, r []Bounder) {
+	c.Check(t.Len(), check.Equals, len(r))
+	var n int
+	t.Do(func(Bounder) (done bool) { n++; return })
+	c.Check(n, check.Equals, len(r))
+	for i := 0; i < 50; i++ {
+		q := randBoxes(1, 0.2)[0].Bounds()
+		c.Check(t.intersectingIDs(q), check.DeepEquals, regionIDs(r, q))
+		p := Point{rand.Float64(), rand.Float64()}
+		c.Check(t.containingIDs(p), check.DeepEquals, regionIDs(r, &Bounding{p, p}))
+	}
+}
+
+func (s *S) TestRegionTree(c *check.C) {
+	r := randBoxes(1000, 0.1)
+	t := NewRegionTree(append([]Bounder(nil), r...))
+	s.checkRegions(c, t, r)
+
+	// Boundary contact is intersection.
+	ids := t.containingIDs(r[0].Bounds()[1].(Point))
+	c.Check(len(ids) != 0 && ids[0] == 0, check.Equals, true)
+
+	var n int
+	c.Check(t.DoIntersecting(func(Bounder) bool { n++; return n == 3 }, &Bounding{Point{0, 0}, Point{1, 1}}), check.Equals, true)
+	c.Check(n, check.Equals, 3)
+
+	var e RegionTree
+	c.Check(e.DoIntersecting(func(Bounder) bool { return true }, &Bounding{Point{0, 0}, Point{1, 1}}), check.Equals, false)
+	c.Check(e.Delete(r[0]), check.Equals, false)
+	c.Check(func() { e.Insert(&nilBox{}) }, check.PanicMatches, "kdtree: nil region bounds")
+}
+
+type nilBox struct{}
+
+func (*nilBox) Bounds() *Bounding { return nil }
+
+func (s *S) TestRegionTreeInsertDelete(c *check.C) {
+	r := randBoxes(1000, 0.1)
+	var t RegionTree
+	for i, v := range r {
+		t.Insert(v)
+		if i%100 == 0 {
+			s.checkRegions(c, &t, r[:i+1])
+		}
+	}
+	s.checkRegions(c, &t, r)
+	stats := t.tree.Stats()
+	c.Check(stats.Height < 30, check.Equals, true, check.Commentf("height=%d", stats.Height))
+
+	// Regions with identical bounds are distinct.
+	dup := &box{id: len(r), b: r[0].(*box).b}
+	t.Insert(dup)
+	r = append(r, dup)
+	s.checkRegions(c, &t, r)
+	c.Check(t.Delete(r[0]), check.Equals, true)
+	c.Check(t.Delete(r[0]), check.Equals, false)
+	r = r[1:]
+	s.checkRegions(c, &t, r)
+
+	for len(r) > 0 {
+		i := rand.Intn(len(r))
+		c.Assert(t.Delete(r[i]), check.Equals, true)
+		r[i] = r[len(r)-1]
+		r = r[:len(r)-1]
+		if len(r)%100 == 0 {
+			s.checkRegions(c, &t, r)
+		}
+	}
+	c.Check(t.tree.Root, check.IsNil)
+}
+
+func BenchmarkRegionTreeIntersecting(b *testing.B) {
+	t := NewRegionTree(randBoxes(1e5, 0.01))
+	queries := randBoxes(100, 0.05)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.DoIntersecting(func(Bounder) (done bool) { return }, queries[i%len(queries)].Bounds())
+	}
+}
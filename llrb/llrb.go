@@ -11,6 +11,10 @@
 //  http://www.teachsolaisgames.com/articles/balanced_left_leaning.html
 package llrb
 
+import (
+	"unsafe"
+)
+
 const (
 	TD234 = iota
 	BU23
@@ -155,6 +159,50 @@ func (t *Tree) Len() int {
 	return t.Count
 }
 
+// Stats holds summary statistics describing the shape of a tree.
+type Stats struct {
+	Nodes       int     // Number of nodes in the tree.
+	Leaves      int     // Number of nodes without children.
+	Height      int     // Number of nodes on the longest path from the root to a leaf.
+	BlackHeight int     // Number of black nodes on each path from the root to a leaf.
+	MeanDepth   float64 // Mean depth of nodes, where the root has depth zero.
+	Bytes       int     // Estimated bytes used by nodes, excluding stored elements.
+}
+
+// Stats returns summary statistics for the Tree.
+func (t *Tree) Stats() Stats {
+	var s Stats
+	s.Height = t.Root.stats(&s, 0)
+	if s.Nodes != 0 {
+		s.MeanDepth /= float64(s.Nodes)
+	}
+	for n := t.Root; n != nil; n = n.Left {
+		if n.color() == Black {
+			s.BlackHeight++
+		}
+	}
+	s.Bytes = s.Nodes * int(unsafe.Sizeof(Node{}))
+	return s
+}
+
+// stats accumulates node counts and the sum of node depths into s, returning
+// the height of the subtree rooted at n.
+func (n *Node) stats(s *Stats, depth int) (height int) {
+	if n == nil {
+		return 0
+	}
+	s.Nodes++
+	s.MeanDepth += float64(depth)
+	if n.Left == nil && n.Right == nil {
+		s.Leaves++
+	}
+	height = n.Left.stats(s, depth+1)
+	if h := n.Right.stats(s, depth+1); h > height {
+		height = h
+	}
+	return height + 1
+}
+
 // Get returns the first match of q in the Tree. If insertion without
 // replacement is used, this is probably not what you want.
 func (t *Tree) Get(q Comparable) Comparable {
@@ -300,6 +300,33 @@ func (s *S) TestRotateRight(c *check.C) {
 	c.Check(tree, check.DeepEquals, rotTree)
 }
 
+func (s *S) TestStats(c *check.C) {
+	c.Check((&Tree{}).Stats(), check.Equals, Stats{})
+
+	st := (&Tree{Root: makeTree("((a,c)b,(e,g)f)d;"), Count: 7}).Stats()
+	st.Bytes = 0
+	c.Check(st, check.Equals, Stats{Nodes: 7, Leaves: 4, Height: 3, MeanDepth: 10.0 / 7})
+
+	t := &Tree{}
+	for i := compRune(0); i < 1000; i++ {
+		t.Insert(i)
+	}
+	st = t.Stats()
+	c.Check(st.Nodes, check.Equals, t.Len())
+	c.Check(st.Height <= 2*st.BlackHeight+1, check.Equals, true)
+	c.Check(st.Height <= 20, check.Equals, true)
+	c.Check(st.MeanDepth < float64(st.Height), check.Equals, true)
+	c.Check(st.Leaves > 0, check.Equals, true)
+	c.Check(st.Bytes >= st.Nodes*int(unsafe.Sizeof(Node{})), check.Equals, true)
+	var black int
+	for n := t.Root; n != nil; n = n.Left {
+		if n.color() == Black {
+			black++
+		}
+	}
+	c.Check(st.BlackHeight, check.Equals, black)
+}
+
 func (s *S) TestNilOperations(c *check.C) {
 	t := &Tree{}
 	c.Check(t.Min(), check.Equals, nil)
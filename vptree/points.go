@@ -0,0 +1,23 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vptree
+
+import "math"
+
+// A Point represents a point in a Euclidean space that satisfies the Comparable interface.
+type Point []float64
+
+// Distance returns the Euclidean distance between p and c. Unlike the kdtree package's
+// Point, the distance is not squared since the squared Euclidean distance does not
+// satisfy the triangle inequality.
+func (p Point) Distance(c Comparable) float64 {
+	q := c.(Point)
+	var sum float64
+	for dim, c := range p {
+		d := c - q[dim]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
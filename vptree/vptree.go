@@ -0,0 +1,351 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vptree implements a vantage point tree. Vantage point trees index values
+// that are only able to report their distance from other values, and so are useful
+// where a k-d tree cannot be used.
+//
+// The vantage point tree is described in 'Data structures and algorithms for nearest
+// neighbor search in general metric spaces.' P. N. Yianilos. Proceedings of the Fourth
+// Annual ACM-SIAM Symposium on Discrete Algorithms. 1993.
+//
+// The ComparableDist, Heap, Keeper, NKeeper and DistKeeper types have the same API and
+// behaviour as the corresponding types of the kdtree package, but are deliberately
+// separate from them. A kdtree.ComparableDist holds a kdtree.Comparable, which must
+// provide coordinates through its Compare and Dims methods, so the values stored in a
+// vantage point tree, which need only provide a Distance method, cannot be held by the
+// kdtree types without changing the kdtree API. Code that handles kdtree search results
+// can be used with this package by changing only the package of the types it names.
+package vptree
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// A Comparable is the element interface for values stored in a vantage point tree.
+type Comparable interface {
+	// Distance returns the distance between the receiver and the parameter.
+	// The returned distance must satisfy the properties of a metric:
+	// it must be non-negative, symmetric and satisfy the triangle
+	// inequality, and the distance between a value and itself must
+	// be zero.
+	Distance(Comparable) float64
+}
+
+// A Node holds a single point value in a vantage point tree. Values in the Closer
+// subtree are no further than Radius from the Point of the node and values in the
+// Further subtree are no closer than Radius.
+type Node struct {
+	Point   Comparable
+	Radius  float64
+	Closer  *Node
+	Further *Node
+}
+
+func (n *Node) String() string {
+	if n == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%v %.3f", n.Point, n.Radius)
+}
+
+// Tree implements a vantage point tree.
+type Tree struct {
+	Root  *Node
+	Count int
+}
+
+// New returns a vantage point tree constructed from the values in p. The effort
+// parameter specifies the number of candidate vantage points that are considered
+// at each node of the tree; the candidate whose distances to a sample of the other
+// values have the greatest spread is chosen. If effort is less than two, vantage
+// points are chosen at random. If src is nil, the default source of the math/rand
+// package is used. The order of the values in p is altered by New.
+func New(p []Comparable, effort int, src rand.Source) *Tree {
+	var rnd *rand.Rand
+	if src != nil {
+		rnd = rand.New(src)
+	}
+	b := builder{effort: effort, rnd: rnd}
+	return &Tree{
+		Root:  b.build(p),
+		Count: len(p),
+	}
+}
+
+// builder holds the parameters of a vantage point tree construction.
+type builder struct {
+	effort int
+	rnd    *rand.Rand
+
+	// dists is the work space for distances
+	// from the vantage point of a node.
+	dists []float64
+}
+
+func (b *builder) build(p []Comparable) *Node {
+	if len(p) == 0 {
+		return nil
+	}
+	v := b.vantage(p)
+	p[0], p[v] = p[v], p[0]
+	n := &Node{Point: p[0]}
+	p = p[1:]
+	if len(p) == 0 {
+		return n
+	}
+
+	b.dists = b.dists[:0]
+	for _, q := range p {
+		b.dists = append(b.dists, n.Point.Distance(q))
+	}
+	sort.Sort(byDist{p: p, dists: b.dists})
+	m := len(p) / 2
+	n.Radius = b.dists[m]
+
+	n.Closer = b.build(p[:m])
+	n.Further = b.build(p[m:])
+	return n
+}
+
+// vantage returns the index of the value in p to use as the vantage point for p.
+func (b *builder) vantage(p []Comparable) int {
+	if b.effort < 2 || len(p) < 3 {
+		return b.intn(len(p))
+	}
+	var (
+		best   int
+		spread = -1.0
+	)
+	for i := 0; i < b.effort; i++ {
+		c := b.intn(len(p))
+		var mean, m2 float64
+		for j := 0; j < b.effort; j++ {
+			d := p[c].Distance(p[b.intn(len(p))])
+			delta := d - mean
+			mean += delta / float64(j+1)
+			m2 += delta * (d - mean)
+		}
+		if m2 > spread {
+			best, spread = c, m2
+		}
+	}
+	return best
+}
+
+// intn returns a random integer in [0, n) from the builder's source.
+func (b *builder) intn(n int) int {
+	if b.rnd == nil {
+		return rand.Intn(n)
+	}
+	return b.rnd.Intn(n)
+}
+
+// byDist sorts a slice of values by their distance from a vantage point.
+type byDist struct {
+	p     []Comparable
+	dists []float64
+}
+
+func (s byDist) Len() int           { return len(s.p) }
+func (s byDist) Less(i, j int) bool { return s.dists[i] < s.dists[j] }
+func (s byDist) Swap(i, j int) {
+	s.p[i], s.p[j] = s.p[j], s.p[i]
+	s.dists[i], s.dists[j] = s.dists[j], s.dists[i]
+}
+
+// Insert adds a point to the tree. Insert does not rebalance the tree.
+func (t *Tree) Insert(c Comparable) {
+	t.Root = t.Root.insert(c)
+	t.Count++
+}
+
+func (n *Node) insert(c Comparable) *Node {
+	if n == nil {
+		return &Node{Point: c}
+	}
+	d := c.Distance(n.Point)
+	switch {
+	case n.Closer == nil && n.Further == nil:
+		n.Radius = d
+		n.Further = &Node{Point: c}
+	case d < n.Radius:
+		n.Closer = n.Closer.insert(c)
+	default:
+		n.Further = n.Further.insert(c)
+	}
+	return n
+}
+
+// Len returns the number of elements in the tree.
+func (t *Tree) Len() int { return t.Count }
+
+// Contains returns whether a Comparable at zero distance from c is in the tree.
+func (t *Tree) Contains(c Comparable) bool {
+	_, d := t.Nearest(c)
+	return d == 0
+}
+
+var inf = math.Inf(1)
+
+// Nearest returns the nearest value to the query and the distance between them.
+func (t *Tree) Nearest(q Comparable) (Comparable, float64) {
+	if t.Root == nil {
+		return nil, inf
+	}
+	k := NewNKeeper(1)
+	t.Root.searchSet(q, k)
+	if k.Max().Comparable == nil {
+		return nil, inf
+	}
+	return k.Max().Comparable, k.Max().Dist
+}
+
+// ComparableDist holds a Comparable and a distance to a specific query. A nil Comparable
+// is used to mark the end of the heap, so clients should not store nil values except for
+// this purpose. ComparableDist corresponds to kdtree.ComparableDist.
+type ComparableDist struct {
+	Comparable Comparable
+	Dist       float64
+}
+
+// Heap is a max heap sorted on Dist.
+type Heap []ComparableDist
+
+func (h *Heap) Max() ComparableDist  { return (*h)[0] }
+func (h *Heap) Len() int             { return len(*h) }
+func (h *Heap) Less(i, j int) bool   { return (*h)[i].Comparable == nil || (*h)[i].Dist > (*h)[j].Dist }
+func (h *Heap) Swap(i, j int)        { (*h)[i], (*h)[j] = (*h)[j], (*h)[i] }
+func (h *Heap) Push(x interface{})   { (*h) = append(*h, x.(ComparableDist)) }
+func (h *Heap) Pop() (i interface{}) { i, *h = (*h)[len(*h)-1], (*h)[:len(*h)-1]; return i }
+
+// NKeeper is a Keeper that retains the n best ComparableDists that it is called to Keep.
+type NKeeper struct {
+	Heap
+}
+
+// NewNKeeper returns an NKeeper with the max value of the heap set to infinite distance. The
+// returned NKeeper is able to retain at most n values.
+func NewNKeeper(n int) *NKeeper {
+	k := NKeeper{make(Heap, 1, n)}
+	k.Heap[0].Dist = inf
+	return &k
+}
+
+// Keep add c to the heap if its distance is less than the maximum value of the heap. If adding
+// c would increase the size of the heap beyond the initial maximum length, the maximum value of
+// the heap is dropped.
+func (k *NKeeper) Keep(c ComparableDist) {
+	if c.Dist < k.Heap[0].Dist {
+		if len(k.Heap) == cap(k.Heap) {
+			heap.Pop(k)
+		}
+		heap.Push(k, c)
+	}
+}
+
+// DistKeeper is a Keeper that retains the ComparableDists within the specified distance of the
+// query that it is called to Keep.
+type DistKeeper struct {
+	Heap
+}
+
+// NewDistKeeper returns an DistKeeper with the max value of the heap set to d.
+func NewDistKeeper(d float64) *DistKeeper { return &DistKeeper{Heap{{Dist: d}}} }
+
+// Keep adds c to the heap if its distance is less than or equal to the max value of the heap.
+func (k *DistKeeper) Keep(c ComparableDist) {
+	if c.Dist <= k.Heap[0].Dist {
+		heap.Push(k, c)
+	}
+}
+
+// Keeper implements a conditional max heap sorted on the Dist field of the ComparableDist type.
+// vantage point search is guided by the distance stored in the max value of the heap.
+type Keeper interface {
+	Keep(ComparableDist) // Keep conditionally pushes the provided ComparableDist onto the heap.
+	Max() ComparableDist // Max returns the maximum element of the Keeper.
+	heap.Interface
+}
+
+// NearestSet finds the nearest values to the query accepted by the provided Keeper, k.
+// k must be able to return a ComparableDist specifying the maximum acceptable distance
+// when Max() is called, and retains the results of the search in min sorted order after
+// the call to NearestSet returns.
+func (t *Tree) NearestSet(k Keeper, q Comparable) {
+	if t.Root == nil {
+		return
+	}
+	t.Root.searchSet(q, k)
+
+	// Check whether we have retained a sentinel
+	// and flag removal if we have.
+	removeSentinel := k.Len() != 0 && k.Max().Comparable == nil
+
+	sort.Sort(sort.Reverse(k))
+
+	// This abuses the interface to drop the max.
+	// It is reasonable to do this because we know
+	// that the maximum value will now be at element
+	// zero, which is removed by the Pop method.
+	if removeSentinel {
+		k.Pop()
+	}
+}
+
+func (n *Node) searchSet(q Comparable, k Keeper) {
+	if n == nil {
+		return
+	}
+
+	d := q.Distance(n.Point)
+	k.Keep(ComparableDist{Comparable: n.Point, Dist: d})
+	if d < n.Radius {
+		n.Closer.searchSet(q, k)
+		if d+k.Max().Dist >= n.Radius {
+			n.Further.searchSet(q, k)
+		}
+		return
+	}
+	n.Further.searchSet(q, k)
+	if d-k.Max().Dist <= n.Radius {
+		n.Closer.searchSet(q, k)
+	}
+}
+
+// An Operation is a function that operates on a Comparable. The tree depth of the point is
+// also provided. If done is returned true, the Operation is indicating that no further work
+// needs to be done and so the Do function should traverse no further.
+type Operation func(Comparable, int) (done bool)
+
+// Do performs fn on all values stored in the tree. A boolean is returned indicating whether the
+// Do traversal was interrupted by an Operation returning true. If fn alters stored values'
+// distance relationships, future tree operation behaviors are undefined.
+func (t *Tree) Do(fn Operation) bool {
+	if t.Root == nil {
+		return false
+	}
+	return t.Root.do(fn, 0)
+}
+
+func (n *Node) do(fn Operation, depth int) (done bool) {
+	if n.Closer != nil {
+		done = n.Closer.do(fn, depth+1)
+		if done {
+			return
+		}
+	}
+	done = fn(n.Point, depth)
+	if done {
+		return
+	}
+	if n.Further != nil {
+		done = n.Further.do(fn, depth+1)
+	}
+	return
+}
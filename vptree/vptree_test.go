@@ -0,0 +1,262 @@
+// Copyright ©2020 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vptree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+// seq is a sequence compared by edit distance.
+type seq string
+
+func (s seq) Distance(c Comparable) float64 {
+	t := c.(seq)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return float64(prev[len(t)])
+}
+
+func minInt(a ...int) int {
+	m := a[0]
+	for _, v := range a[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func randSeqs(n, length int, rnd *rand.Rand) []Comparable {
+	const alphabet = "acgt"
+	p := make([]Comparable, n)
+	for i := range p {
+		b := make([]byte, length-rnd.Intn(3))
+		for j := range b {
+			b[j] = alphabet[rnd.Intn(len(alphabet))]
+		}
+		p[i] = seq(b)
+	}
+	return p
+}
+
+func randPoints(n int, rnd *rand.Rand) []Comparable {
+	p := make([]Comparable, n)
+	for i := range p {
+		p[i] = Point{rnd.Float64(), rnd.Float64(), rnd.Float64()}
+	}
+	return p
+}
+
+// sortedDists returns the distances from q to each of the values in p in ascending order.
+func sortedDists(q Comparable, p []Comparable) []float64 {
+	d := make([]float64, len(p))
+	for i, v := range p {
+		d[i] = q.Distance(v)
+	}
+	sort.Float64s(d)
+	return d
+}
+
+// isVPTree returns whether the subtree rooted at n satisfies the vantage point
+// tree invariant, and the number of values held in the subtree.
+func (n *Node) isVPTree() (ok bool, count int) {
+	if n == nil {
+		return true, 0
+	}
+	var held []Comparable
+	collect := func(m *Node) {
+		if m != nil {
+			m.do(func(c Comparable, _ int) bool { held = append(held, c); return false }, 0)
+		}
+	}
+	collect(n.Closer)
+	for _, c := range held {
+		if n.Point.Distance(c) > n.Radius {
+			return false, 0
+		}
+	}
+	held = held[:0]
+	collect(n.Further)
+	for _, c := range held {
+		if n.Point.Distance(c) < n.Radius {
+			return false, 0
+		}
+	}
+	lok, lc := n.Closer.isVPTree()
+	rok, rc := n.Further.isVPTree()
+	return lok && rok, lc + rc + 1
+}
+
+func (s *S) TestNew(c *check.C) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, effort := range []int{0, 2, 10} {
+		for _, p := range [][]Comparable{
+			nil,
+			randPoints(1, rnd),
+			randPoints(1000, rnd),
+			randSeqs(500, 12, rnd),
+		} {
+			t := New(append([]Comparable(nil), p...), effort, rnd)
+			c.Check(t.Len(), check.Equals, len(p))
+			ok, count := t.Root.isVPTree()
+			c.Check(ok, check.Equals, true)
+			c.Check(count, check.Equals, len(p))
+		}
+	}
+
+	// Construction is deterministic for a given source.
+	p := randPoints(100, rnd)
+	a := New(append([]Comparable(nil), p...), 5, rand.NewSource(2))
+	b := New(append([]Comparable(nil), p...), 5, rand.NewSource(2))
+	c.Check(a, check.DeepEquals, b)
+}
+
+func (s *S) TestInsert(c *check.C) {
+	rnd := rand.New(rand.NewSource(1))
+	p := randPoints(500, rnd)
+	t := New(append([]Comparable(nil), p[:100]...), 0, rnd)
+	for _, v := range p[100:] {
+		t.Insert(v)
+	}
+	c.Check(t.Len(), check.Equals, len(p))
+	ok, count := t.Root.isVPTree()
+	c.Check(ok, check.Equals, true)
+	c.Check(count, check.Equals, len(p))
+	for _, v := range p {
+		c.Check(t.Contains(v), check.Equals, true)
+	}
+	c.Check(t.Contains(Point{2, 2, 2}), check.Equals, false)
+
+	var e Tree
+	e.Insert(seq("acgt"))
+	e.Insert(seq("acct"))
+	e.Insert(seq("tttt"))
+	ok, count = e.Root.isVPTree()
+	c.Check(ok, check.Equals, true)
+	c.Check(count, check.Equals, 3)
+}
+
+func (s *S) TestNearest(c *check.C) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		data  []Comparable
+		query func() Comparable
+	}{
+		{
+			data:  randPoints(1000, rnd),
+			query: func() Comparable { return randPoints(1, rnd)[0] },
+		},
+		{
+			data:  randSeqs(500, 12, rnd),
+			query: func() Comparable { return randSeqs(1, 12, rnd)[0] },
+		},
+	} {
+		t := New(append([]Comparable(nil), test.data...), 5, rnd)
+		for i := 0; i < 50; i++ {
+			q := test.query()
+			want := sortedDists(q, test.data)
+
+			p, d := t.Nearest(q)
+			c.Check(d, check.Equals, want[0])
+			c.Check(q.Distance(p), check.Equals, d)
+
+			nk := NewNKeeper(10)
+			t.NearestSet(nk, q)
+			c.Assert(nk.Len(), check.Equals, 10)
+			for j, e := range nk.Heap {
+				c.Check(e.Dist, check.Equals, want[j])
+				c.Check(q.Distance(e.Comparable), check.Equals, e.Dist)
+			}
+
+			r := want[20]
+			dk := NewDistKeeper(r)
+			t.NearestSet(dk, q)
+			var n int
+			for _, d := range want {
+				if d <= r {
+					n++
+				}
+			}
+			c.Check(dk.Len(), check.Equals, n)
+			for j := 1; j < dk.Len(); j++ {
+				c.Check(dk.Heap[j-1].Dist <= dk.Heap[j].Dist, check.Equals, true)
+			}
+		}
+	}
+
+	var e Tree
+	p, d := e.Nearest(Point{0, 0, 0})
+	c.Check(p, check.IsNil)
+	c.Check(d, check.Equals, math.Inf(1))
+	nk := NewNKeeper(3)
+	e.NearestSet(nk, Point{0, 0, 0})
+	c.Check(nk.Len(), check.Equals, 1)
+}
+
+func (s *S) TestDo(c *check.C) {
+	rnd := rand.New(rand.NewSource(1))
+	p := randSeqs(100, 8, rnd)
+	t := New(append([]Comparable(nil), p...), 0, rnd)
+	var got []string
+	c.Check(t.Do(func(v Comparable, _ int) (done bool) {
+		got = append(got, string(v.(seq)))
+		return
+	}), check.Equals, false)
+	want := make([]string, len(p))
+	for i, v := range p {
+		want[i] = string(v.(seq))
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	c.Check(got, check.DeepEquals, want)
+
+	var n int
+	c.Check(t.Do(func(Comparable, int) bool { n++; return n == 10 }), check.Equals, true)
+	c.Check(n, check.Equals, 10)
+	c.Check((&Tree{}).Do(func(Comparable, int) bool { return true }), check.Equals, false)
+}
+
+func BenchmarkNew(b *testing.B) {
+	p := randPoints(1e5, rand.New(rand.NewSource(1)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New(p, 5, rand.NewSource(1))
+	}
+}
+
+func BenchmarkNearestSet(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	t := New(randPoints(1e5, rnd), 5, rnd)
+	queries := randPoints(100, rnd)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.NearestSet(NewNKeeper(10), queries[i%len(queries)])
+	}
+}